@@ -1,5 +1,33 @@
 package alterx
 
+import (
+	"fmt"
+	"strings"
+)
+
+// Attack modes define how values of multiple payloads are combined
+const (
+	// ClusterBombMode generates all combinations of payloads (cartesian product)
+	ClusterBombMode = "clusterbomb"
+	// PitchforkMode pairs values of payloads index by index (zip)
+	PitchforkMode = "pitchfork"
+	// SniperMode varies one payload at a time while others are fixed to their first value
+	SniperMode = "sniper"
+)
+
+// AttackModes contains all available attack modes
+var AttackModes = []string{ClusterBombMode, PitchforkMode, SniperMode}
+
+// validateAttackMode checks if given attack mode is supported
+func validateAttackMode(mode string) error {
+	for _, v := range AttackModes {
+		if v == mode {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown attack mode '%s': supported values are %s", mode, strings.Join(AttackModes, ","))
+}
+
 // ClusterBomb generates all combinations of payloads using an Nth-order ClusterBomb algorithm.
 // It uses recursion to construct permutations efficiently while avoiding stack overflows.
 //
@@ -12,6 +40,9 @@ package alterx
 //  3. Use recursion to construct all possible combinations
 //  4. At the final recursion level, iterate through remaining values and invoke callback
 //
+// Payload values are read lazily from generators at each recursion level, so generators
+// like Range are never materialized in memory.
+//
 // Example:
 //
 //	Given payloads["word"] = []string{"api", "dev", "cloud"}
@@ -28,8 +59,9 @@ func ClusterBomb(payloads *IndexMap, callback func(varMap map[string]interface{}
 
 		// Fill in the final missing element and invoke callback
 		index := len(Vector)
-		for _, elem := range payloads.GetNth(index) {
-			vectorMap[payloads.KeyAtNth(index)] = elem
+		generator := payloads.GeneratorAtNth(index)
+		for i := 0; i < generator.Len(); i++ {
+			vectorMap[payloads.KeyAtNth(index)] = generator.At(i)
 			if !callback(vectorMap) {
 				return false // Early termination requested
 			}
@@ -39,11 +71,12 @@ func ClusterBomb(payloads *IndexMap, callback func(varMap map[string]interface{}
 
 	// Recursive case: Build up the vector by iterating through payloads at current index
 	index := len(Vector)
-	for _, v := range payloads.GetNth(index) {
+	generator := payloads.GeneratorAtNth(index)
+	for i := 0; i < generator.Len(); i++ {
 		// Pre-allocate capacity to reduce allocations
 		tmp := make([]string, len(Vector), len(Vector)+1)
 		copy(tmp, Vector)
-		tmp = append(tmp, v)
+		tmp = append(tmp, generator.At(i))
 
 		if !ClusterBomb(payloads, callback, tmp) {
 			return false // Propagate early termination
@@ -52,16 +85,157 @@ func ClusterBomb(payloads *IndexMap, callback func(varMap map[string]interface{}
 	return true
 }
 
+// Pitchfork generates payloads by pairing values of all payloads at same index.
+// Number of generated permutations is equal to length of shortest payload
+//
+// Example:
+//
+//	Given payloads["env"] = []string{"production", "staging"}
+//	and payloads["envshort"] = []string{"prod", "stg"}
+//	This generates: production-prod, staging-stg
+func Pitchfork(payloads *IndexMap, callback func(varMap map[string]interface{}) bool) bool {
+	if payloads.Cap() == 0 {
+		return true
+	}
+	size := -1
+	for i := 0; i < payloads.Cap(); i++ {
+		if n := payloads.GeneratorAtNth(i).Len(); size < 0 || n < size {
+			size = n
+		}
+	}
+	vectorMap := make(map[string]interface{}, payloads.Cap())
+	for index := 0; index < size; index++ {
+		for i := 0; i < payloads.Cap(); i++ {
+			vectorMap[payloads.KeyAtNth(i)] = payloads.GeneratorAtNth(i).At(index)
+		}
+		if !callback(vectorMap) {
+			return false
+		}
+	}
+	return true
+}
+
+// Sniper generates payloads by iterating over values of one payload at a time
+// while all other payloads are fixed to their first (default) value.
+// Combination of all default values is only generated once
+//
+// Example:
+//
+//	Given payloads["word"] = []string{"api", "dev"}
+//	and payloads["number"] = []string{"1", "2"}
+//	This generates: api-1, dev-1, api-2
+func Sniper(payloads *IndexMap, callback func(varMap map[string]interface{}) bool) bool {
+	vectorMap := make(map[string]interface{}, payloads.Cap())
+	for i := 0; i < payloads.Cap(); i++ {
+		generator := payloads.GeneratorAtNth(i)
+		if generator.Len() == 0 {
+			// there is no default value for empty payload
+			return true
+		}
+		vectorMap[payloads.KeyAtNth(i)] = generator.At(0)
+	}
+	for i := 0; i < payloads.Cap(); i++ {
+		key, generator := payloads.KeyAtNth(i), payloads.GeneratorAtNth(i)
+		start := 1
+		if i == 0 {
+			// combination of default values
+			start = 0
+		}
+		for index := start; index < generator.Len(); index++ {
+			vectorMap[key] = generator.At(index)
+			if !callback(vectorMap) {
+				return false
+			}
+		}
+		vectorMap[key] = generator.At(0)
+	}
+	return true
+}
+
+// countPermutations returns number of permutations generated by attack mode
+// for payloads of given lengths
+func countPermutations(mode string, lengths []int) int {
+	if len(lengths) == 0 {
+		return 1
+	}
+	switch mode {
+	case PitchforkMode:
+		size := lengths[0]
+		for _, v := range lengths[1:] {
+			if v < size {
+				size = v
+			}
+		}
+		return size
+	case SniperMode:
+		counter := 1
+		for _, v := range lengths {
+			if v == 0 {
+				return 0
+			}
+			counter += v - 1
+		}
+		return counter
+	default:
+		counter := 1
+		for _, v := range lengths {
+			counter *= v
+		}
+		return counter
+	}
+}
+
+// Generator lazily produces values of a payload variable
+type Generator interface {
+	// Len returns number of values produced by generator
+	Len() int
+	// At returns value at given index
+	At(i int) string
+}
+
+// Wordlist is a Generator backed by a slice of words
+type Wordlist []string
+
+// Len returns number of words in wordlist
+func (w Wordlist) Len() int {
+	return len(w)
+}
+
+// At returns word at given index
+func (w Wordlist) At(i int) string {
+	return w[i]
+}
+
 // IndexMap provides indexed access to a map, allowing retrieval by numeric position.
 // This is useful when you need deterministic iteration order over map keys.
 type IndexMap struct {
-	values  map[string][]string
+	values  map[string]Generator
 	indexes map[int]string
 }
 
 // GetNth returns the slice of values at the nth position in the map
+// values of lazy generators are materialized
 func (o *IndexMap) GetNth(n int) []string {
-	return o.values[o.indexes[n]]
+	generator, ok := o.values[o.indexes[n]]
+	if !ok {
+		return nil
+	}
+	if words, ok := generator.(Wordlist); ok {
+		return words
+	}
+	values := make([]string, 0, generator.Len())
+	for i := 0; i < generator.Len(); i++ {
+		values = append(values, generator.At(i))
+	}
+	return values
+}
+
+// GeneratorAtNth returns the generator at the nth position in the map
+func (o *IndexMap) GeneratorAtNth(n int) Generator {
+	if generator, ok := o.values[o.indexes[n]]; ok {
+		return generator
+	}
+	return Wordlist(nil)
 }
 
 // Cap returns the number of keys in the IndexMap
@@ -77,6 +251,15 @@ func (o *IndexMap) KeyAtNth(n int) string {
 // NewIndexMap creates an IndexMap that allows elements to be retrieved by a fixed numeric index.
 // This provides deterministic ordering for map iteration, which is useful for reproducible results.
 func NewIndexMap(values map[string][]string) *IndexMap {
+	generators := make(map[string]Generator, len(values))
+	for k, v := range values {
+		generators[k] = Wordlist(v)
+	}
+	return NewGeneratorIndexMap(generators)
+}
+
+// NewGeneratorIndexMap creates an IndexMap from lazily evaluated payload generators
+func NewGeneratorIndexMap(values map[string]Generator) *IndexMap {
 	i := &IndexMap{
 		values:  values,
 		indexes: make(map[int]string, len(values)),
@@ -153,6 +153,86 @@ func TestClusterBomb(t *testing.T) {
 	})
 }
 
+func TestClusterBombGenerators(t *testing.T) {
+	payloads := map[string]Generator{
+		"word":   Wordlist{"api", "dev"},
+		"number": &Range{Start: 1, End: 3, Width: 2},
+	}
+	indexMap := NewGeneratorIndexMap(payloads)
+
+	var results []string
+	callback := func(varMap map[string]interface{}) bool {
+		results = append(results, Replace("{{word}}{{number}}", varMap))
+		return true
+	}
+
+	success := ClusterBomb(indexMap, callback, []string{})
+	require.True(t, success)
+	require.ElementsMatch(t, []string{"api01", "api02", "api03", "dev01", "dev02", "dev03"}, results)
+}
+
+func TestPitchfork(t *testing.T) {
+	t.Run("pairs values by index", func(t *testing.T) {
+		indexMap := NewIndexMap(map[string][]string{
+			"env":      {"production", "staging", "development"},
+			"envshort": {"prod", "stg"},
+		})
+		var results []string
+		success := Pitchfork(indexMap, func(varMap map[string]interface{}) bool {
+			results = append(results, Replace("{{env}}-{{envshort}}", varMap))
+			return true
+		})
+		require.True(t, success)
+		require.Equal(t, []string{"production-prod", "staging-stg"}, results)
+	})
+
+	t.Run("early termination", func(t *testing.T) {
+		indexMap := NewIndexMap(map[string][]string{"word": {"a", "b", "c"}})
+		count := 0
+		success := Pitchfork(indexMap, func(varMap map[string]interface{}) bool {
+			count++
+			return false
+		})
+		require.False(t, success)
+		require.Equal(t, 1, count)
+	})
+}
+
+func TestSniper(t *testing.T) {
+	t.Run("varies one payload at a time", func(t *testing.T) {
+		indexMap := NewGeneratorIndexMap(map[string]Generator{
+			"word":   Wordlist{"api", "dev", "prod"},
+			"number": &Range{Start: 1, End: 3},
+		})
+		var results []string
+		success := Sniper(indexMap, func(varMap map[string]interface{}) bool {
+			results = append(results, Replace("{{word}}{{number}}", varMap))
+			return true
+		})
+		require.True(t, success)
+		require.ElementsMatch(t, []string{"api1", "dev1", "prod1", "api2", "api3"}, results)
+	})
+
+	t.Run("empty payload", func(t *testing.T) {
+		indexMap := NewIndexMap(map[string][]string{"word": {"api"}, "number": {}})
+		count := 0
+		require.True(t, Sniper(indexMap, func(varMap map[string]interface{}) bool {
+			count++
+			return true
+		}))
+		require.Equal(t, 0, count)
+	})
+}
+
+func TestCountPermutations(t *testing.T) {
+	lengths := []int{3, 2, 4}
+	require.Equal(t, 24, countPermutations(ClusterBombMode, lengths))
+	require.Equal(t, 2, countPermutations(PitchforkMode, lengths))
+	require.Equal(t, 7, countPermutations(SniperMode, lengths))
+	require.Equal(t, 0, countPermutations(SniperMode, []int{3, 0}))
+	require.Equal(t, 1, countPermutations(PitchforkMode, nil))
+}
+
 func TestNewIndexMap(t *testing.T) {
 	t.Run("basic creation", func(t *testing.T) {
 		values := map[string][]string{
@@ -189,6 +269,13 @@ func TestNewIndexMap(t *testing.T) {
 		require.NotEqual(t, key0, key1)
 	})
 
+	t.Run("get nth element of generator", func(t *testing.T) {
+		indexMap := NewGeneratorIndexMap(map[string]Generator{"number": &Range{Start: 1, End: 3}})
+		require.Equal(t, []string{"1", "2", "3"}, indexMap.GetNth(0))
+		require.Equal(t, 3, indexMap.GeneratorAtNth(0).Len())
+		require.Equal(t, 0, indexMap.GeneratorAtNth(1).Len())
+	})
+
 	t.Run("empty map", func(t *testing.T) {
 		values := map[string][]string{}
 		indexMap := NewIndexMap(values)
@@ -210,6 +297,7 @@ func TestIndexMapDeterminism(t *testing.T) {
 
 	// The ordering might be different between im1 and im2 (maps are unordered)
 	// but each IndexMap should be internally consistent
+	require.Equal(t, im1.Cap(), im2.Cap())
 	for i := 0; i < im1.Cap(); i++ {
 		require.Equal(t, im1.KeyAtNth(i), im1.KeyAtNth(i), "IndexMap should be consistent with itself")
 		require.Equal(t, im1.GetNth(i), im1.GetNth(i), "IndexMap should return same values for same index")
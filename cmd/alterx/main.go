@@ -16,13 +16,24 @@ func main() {
 	cliOpts := runner.ParseFlags()
 
 	alterOpts := alterx.Options{
-		Domains:       cliOpts.Domains,
-		Patterns:      cliOpts.Patterns,
-		Payloads:      cliOpts.Payloads,
-		Limit:         cliOpts.Limit,
-		Enrich:        cliOpts.Enrich,
-		MaxSize:       cliOpts.MaxSize,
-		DedupeResults: true, // Enable deduplication by default
+		Domains:         cliOpts.Domains,
+		DomainChan:      cliOpts.DomainChan,
+		InputFormat:     cliOpts.InputFormat,
+		InputColumn:     cliOpts.InputColumn,
+		Patterns:        cliOpts.Patterns,
+		Payloads:        cliOpts.Payloads,
+		Limit:           cliOpts.Limit,
+		Enrich:          cliOpts.Enrich,
+		EnrichFrom:      cliOpts.EnrichFrom,
+		PerLevel:        cliOpts.PerLevel,
+		LevelMutations:  cliOpts.LevelMutations,
+		WildcardPayload: cliOpts.WildcardPayload,
+		TokenSwap:       cliOpts.TokenSwap,
+		SeriesWindow:    cliOpts.SeriesWindow,
+		Mode:            cliOpts.Mode,
+		Validation:      cliOpts.Validation,
+		IDNOutput:       cliOpts.IDNOutput,
+		MaxSize:         cliOpts.MaxSize,
 	}
 
 	if cliOpts.PermutationConfig != "" {
@@ -37,6 +48,40 @@ func main() {
 		if len(config.Payloads) > 0 {
 			alterOpts.Payloads = config.Payloads
 		}
+		if len(config.Ranges) > 0 {
+			alterOpts.Ranges = config.Ranges
+		}
+		if len(config.Tuples) > 0 {
+			alterOpts.Tuples = config.Tuples
+		}
+		if config.Rules != nil {
+			alterOpts.Rules = config.Rules
+		}
+		if config.Tokenizer != nil {
+			alterOpts.Tokenizer = config.Tokenizer
+		}
+		if len(config.Classes) > 0 {
+			alterOpts.Classes = config.Classes
+		}
+		if config.Enrich != nil {
+			alterOpts.Enrichment = config.Enrich
+		}
+	}
+	alterOpts.Enrichment = enrichment(cliOpts, alterOpts.Enrichment)
+
+	if cliOpts.SuffixList != "" || len(cliOpts.Roots) > 0 {
+		suffixes := alterx.NewSuffixList()
+		if cliOpts.SuffixList != "" {
+			if err := suffixes.LoadFile(cliOpts.SuffixList); err != nil {
+				gologger.Fatal().Msgf("failed to read suffix list '%s': %v", cliOpts.SuffixList, err)
+			}
+		}
+		for _, root := range cliOpts.Roots {
+			if err := suffixes.AddRoot(root); err != nil {
+				gologger.Fatal().Msgf("failed to add root: %v", err)
+			}
+		}
+		alterOpts.SuffixList = suffixes
 	}
 
 	// Configure output writer
@@ -58,6 +103,11 @@ func main() {
 		output = os.Stdout
 	}
 
+	if cliOpts.Learn {
+		learnPatterns(&alterOpts, output)
+		return
+	}
+
 	// Create new alterx instance with options
 	m, err := alterx.New(&alterOpts)
 	if err != nil {
@@ -93,3 +143,59 @@ func main() {
 		gologger.Fatal().Msgf("failed to generate permutations: %v", err)
 	}
 }
+
+// learnPatterns infers patterns from input subdomains and writes them as permutation config
+func learnPatterns(opts *alterx.Options, output io.Writer) {
+	parser, err := alterx.NewInputParser(opts.InputFormat, opts.InputColumn)
+	if err != nil {
+		gologger.Fatal().Msgf("failed to learn patterns: %v", err)
+	}
+	var domains []string
+	for _, line := range opts.Domains {
+		records, err := parser.Parse(line)
+		if err != nil {
+			gologger.Warning().Msgf("failed to parse input %s: %v", line, err)
+		}
+		for _, record := range records {
+			domains = append(domains, record.Domain)
+		}
+	}
+	learned, err := alterx.Learn(domains, &alterx.LearnOptions{Tokenizer: opts.Tokenizer, SuffixList: opts.SuffixList})
+	if err != nil {
+		gologger.Fatal().Msgf("failed to learn patterns: %v", err)
+	}
+	bin, err := learned.YAML()
+	if err != nil {
+		gologger.Fatal().Msgf("failed to encode learned config: %v", err)
+	}
+	if _, err := output.Write(bin); err != nil {
+		gologger.Fatal().Msgf("failed to write learned config: %v", err)
+	}
+	gologger.Info().Msgf("Learned %d patterns from %d subdomains", len(learned.Patterns), len(domains))
+}
+
+// enrichment overrides enrichment thresholds of permutation config with cli options
+func enrichment(cliOpts *runner.Options, enrichment *alterx.Enrichment) *alterx.Enrichment {
+	if cliOpts.EnrichKey == "" && cliOpts.EnrichMinCount == 0 && cliOpts.EnrichMinLength == 0 && cliOpts.EnrichMaxWords == 0 && !cliOpts.EnrichCorpusWords {
+		return enrichment
+	}
+	if enrichment == nil {
+		enrichment = &alterx.Enrichment{}
+	}
+	if cliOpts.EnrichKey != "" {
+		enrichment.Key = cliOpts.EnrichKey
+	}
+	if cliOpts.EnrichMinCount > 0 {
+		enrichment.MinCount = cliOpts.EnrichMinCount
+	}
+	if cliOpts.EnrichMinLength > 0 {
+		enrichment.MinLength = cliOpts.EnrichMinLength
+	}
+	if cliOpts.EnrichMaxWords > 0 {
+		enrichment.MaxWords = cliOpts.EnrichMaxWords
+	}
+	if cliOpts.EnrichCorpusWords {
+		enrichment.CorpusWords = true
+	}
+	return enrichment
+}
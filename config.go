@@ -1,6 +1,7 @@
 package alterx
 
 import (
+	"fmt"
 	"os"
 	"strings"
 
@@ -20,6 +21,54 @@ var DefaultConfig Config
 type Config struct {
 	Patterns []string            `yaml:"patterns"`
 	Payloads map[string][]string `yaml:"payloads"`
+	Ranges   map[string]*Range   `yaml:"ranges,omitempty"`
+	Rules    *Rules              `yaml:"rules,omitempty"`
+	// Classes are semantic classes of tokens swapped with each other (ex: env: [dev, stg, prod])
+	Classes map[string][]string `yaml:"classes,omitempty"`
+	// Tokenizer splits sub of inputs into {{subtokN}} variables
+	Tokenizer *Tokenizer `yaml:"tokenizer,omitempty"`
+	// Enrich contains thresholds and target payload of words extracted by enrich
+	Enrich *Enrichment `yaml:"enrich,omitempty"`
+	// Tuples contains payloads with structured entries (ex: - {env: production, abbr: prod})
+	// they are defined in payloads section and separated while decoding config
+	Tuples map[string]Tuples `yaml:"-"`
+}
+
+// UnmarshalYAML decodes config and separates payloads with structured entries into tuples
+func (c *Config) UnmarshalYAML(value *yaml.Node) error {
+	var raw struct {
+		Patterns  []string             `yaml:"patterns"`
+		Payloads  map[string]yaml.Node `yaml:"payloads"`
+		Ranges    map[string]*Range    `yaml:"ranges"`
+		Rules     *Rules               `yaml:"rules"`
+		Tokenizer *Tokenizer           `yaml:"tokenizer"`
+		Classes   map[string][]string  `yaml:"classes"`
+		Enrich    *Enrichment          `yaml:"enrich"`
+	}
+	if err := value.Decode(&raw); err != nil {
+		return err
+	}
+	c.Patterns, c.Ranges, c.Rules, c.Tokenizer = raw.Patterns, raw.Ranges, raw.Rules, raw.Tokenizer
+	c.Classes, c.Enrich = raw.Classes, raw.Enrich
+	for k, node := range raw.Payloads {
+		var words []string
+		if err := node.Decode(&words); err == nil {
+			if c.Payloads == nil {
+				c.Payloads = map[string][]string{}
+			}
+			c.Payloads[k] = words
+			continue
+		}
+		var tuples Tuples
+		if err := node.Decode(&tuples); err != nil {
+			return fmt.Errorf("payload '%s' must be a list of words or a list of key-value entries", k)
+		}
+		if c.Tuples == nil {
+			c.Tuples = map[string]Tuples{}
+		}
+		c.Tuples[k] = tuples
+	}
+	return nil
 }
 
 // NewConfig reads config from file
@@ -33,9 +82,17 @@ func NewConfig(filePath string) (*Config, error) {
 		return nil, err
 	}
 
+	if len(cfg.Payloads["word"]) == 0 {
+		return &cfg, nil
+	}
 	var words []string
 	for _, p := range cfg.Payloads["word"] {
 		if !fileutil.FileExists(p) {
+			if strings.ContainsRune(p, os.PathSeparator) {
+				// words can never contain path separator so this is a missing wordlist
+				gologger.Error().Msgf("wordlist %v not found, skipping", p)
+				continue
+			}
 			words = append(words, p)
 		} else {
 			wordBytes, err := os.ReadFile(p)
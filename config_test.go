@@ -201,3 +201,146 @@ payloads:
 	require.Len(t, cfg.Payloads["word"], 2)
 	require.Len(t, cfg.Payloads["number"], 3)
 }
+
+func TestConfigRanges(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+
+	configContent := `patterns:
+  - "{{sub}}{{node}}.{{suffix}}"
+ranges:
+  node:
+    start: 1
+    end: 50
+    step: 2
+    width: 2
+  hex:
+    start: 0
+    end: 255
+    base: 16
+`
+	err := os.WriteFile(configPath, []byte(configContent), 0644)
+	require.NoError(t, err)
+
+	cfg, err := NewConfig(configPath)
+	require.NoError(t, err)
+
+	require.Equal(t, &Range{Start: 1, End: 50, Step: 2, Width: 2}, cfg.Ranges["node"])
+	require.Equal(t, &Range{Start: 0, End: 255, Base: 16}, cfg.Ranges["hex"])
+}
+
+func TestConfigTuples(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+
+	configContent := `patterns:
+  - "{{sub}}-{{env.abbr}}.{{env.region}}.{{suffix}}"
+payloads:
+  word:
+    - dev
+  env:
+    - {name: production, abbr: prod, region: us-east-1}
+    - name: staging
+      abbr: stg
+      region: eu-west-1
+`
+	err := os.WriteFile(configPath, []byte(configContent), 0644)
+	require.NoError(t, err)
+
+	cfg, err := NewConfig(configPath)
+	require.NoError(t, err)
+
+	require.Equal(t, map[string][]string{"word": {"dev"}}, cfg.Payloads)
+	require.Equal(t, Tuples{
+		{"name": "production", "abbr": "prod", "region": "us-east-1"},
+		{"name": "staging", "abbr": "stg", "region": "eu-west-1"},
+	}, cfg.Tuples["env"])
+
+	t.Run("mixed entries", func(t *testing.T) {
+		configPath := filepath.Join(tmpDir, "mixed.yaml")
+		err := os.WriteFile(configPath, []byte("payloads:\n  env:\n    - prod\n    - {abbr: stg}\n"), 0644)
+		require.NoError(t, err)
+		_, err = NewConfig(configPath)
+		require.Error(t, err)
+	})
+}
+
+func TestConfigRules(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+
+	configContent := `rules:
+  deny:
+    - "^test-"
+  allow:
+    - "^[a-z0-9-]+\\."
+  forbidden:
+    - "--"
+  distinct-from-sub: true
+  max-repeat: 1
+`
+	err := os.WriteFile(configPath, []byte(configContent), 0644)
+	require.NoError(t, err)
+
+	cfg, err := NewConfig(configPath)
+	require.NoError(t, err)
+	require.Equal(t, &Rules{
+		Deny:            []string{"^test-"},
+		Allow:           []string{`^[a-z0-9-]+\.`},
+		Forbidden:       []string{"--"},
+		DistinctFromSub: true,
+		MaxRepeat:       1,
+	}, cfg.Rules)
+}
+
+func TestConfigTokenizer(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+
+	configContent := `tokenizer:
+  separators: "-_."
+  keep-digits: true
+`
+	err := os.WriteFile(configPath, []byte(configContent), 0644)
+	require.NoError(t, err)
+
+	cfg, err := NewConfig(configPath)
+	require.NoError(t, err)
+	require.Equal(t, &Tokenizer{Separators: "-_.", KeepDigits: true}, cfg.Tokenizer)
+}
+
+func TestConfigClasses(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+
+	configContent := `classes:
+  env: [dev, stg, prod]
+  version: [v1, v2]
+`
+	err := os.WriteFile(configPath, []byte(configContent), 0644)
+	require.NoError(t, err)
+
+	cfg, err := NewConfig(configPath)
+	require.NoError(t, err)
+	require.Equal(t, map[string][]string{"env": {"dev", "stg", "prod"}, "version": {"v1", "v2"}}, cfg.Classes)
+	require.Contains(t, DefaultConfig.Classes, "env")
+}
+
+func TestConfigEnrich(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+
+	configContent := `enrich:
+  key: enriched
+  min-count: 2
+  min-length: 4
+  max-words: 100
+  corpus-words: true
+`
+	err := os.WriteFile(configPath, []byte(configContent), 0644)
+	require.NoError(t, err)
+
+	cfg, err := NewConfig(configPath)
+	require.NoError(t, err)
+	require.Equal(t, &Enrichment{Key: "enriched", MinCount: 2, MinLength: 4, MaxWords: 100, CorpusWords: true}, cfg.Enrich)
+}
@@ -0,0 +1,141 @@
+package alterx
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/projectdiscovery/gologger"
+)
+
+// maxCorpusFileSize is maximum size of files scanned for enrichment
+const maxCorpusFileSize = 64 * 1024 * 1024
+
+// extractHostnames matches dot separated names in text (ex: api.example.com in https://api.example.com/v1)
+var extractHostnames = regexp.MustCompile(`[a-zA-Z0-9_-]+(?:\.[a-zA-Z0-9_-]+)+`)
+
+// corpusFiles returns files of given paths where directories are walked recursively
+func corpusFiles(paths []string) ([]string, error) {
+	var files []string
+	for _, path := range paths {
+		err := filepath.WalkDir(path, func(file string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.Type().IsRegular() {
+				files = append(files, file)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to read enrich source %v: %w", path, err)
+		}
+	}
+	return files, nil
+}
+
+// corpusText returns text of file along with names of pem encoded certificates it contains
+// since names of certificates are not readable in base64 encoded form
+func corpusText(bin []byte) string {
+	var names []string
+	rest := bin
+	for bytes.Contains(rest, []byte("-----BEGIN")) {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			continue
+		}
+		names = append(names, cert.Subject.CommonName)
+		names = append(names, cert.DNSNames...)
+	}
+	if len(names) == 0 {
+		return string(bin)
+	}
+	return string(bin) + "\n" + strings.Join(names, "\n")
+}
+
+// findHostnames returns names in text that are subdomains of given roots
+func findHostnames(text string, roots map[string]struct{}) []string {
+	var hostnames []string
+	seen := map[string]struct{}{}
+	for _, v := range extractHostnames.FindAllString(text, -1) {
+		hostname := strings.Trim(strings.ToLower(v), "-")
+		if _, ok := seen[hostname]; ok {
+			continue
+		}
+		for i := strings.IndexByte(hostname, '.'); i >= 0; {
+			if _, ok := roots[hostname[i+1:]]; ok {
+				seen[hostname] = struct{}{}
+				hostnames = append(hostnames, hostname)
+				break
+			}
+			next := strings.IndexByte(hostname[i+1:], '.')
+			if next < 0 {
+				break
+			}
+			i += next + 1
+		}
+	}
+	return hostnames
+}
+
+// scanCorpora scans files of EnrichFrom for hostnames under roots of inputs
+// hostnames are added to inputs so words of their labels are used by enrich.
+// if corpus is not nil, all words of files are counted by it
+func (m *Mutator) scanCorpora(corpus *wordCounter) error {
+	files, err := corpusFiles(m.Options.EnrichFrom)
+	if err != nil {
+		return err
+	}
+	roots := map[string]struct{}{}
+	for _, v := range m.Inputs {
+		roots[v.Root] = struct{}{}
+	}
+	added := 0
+	for _, file := range files {
+		if info, err := os.Stat(file); err == nil && info.Size() > maxCorpusFileSize {
+			gologger.Warning().Msgf("skipping enrich source %v larger than %d bytes", file, maxCorpusFileSize)
+			continue
+		}
+		bin, err := os.ReadFile(file)
+		if err != nil {
+			gologger.Warning().Msgf("failed to read enrich source %v got %v", file, err)
+			continue
+		}
+		text := corpusText(bin)
+		if corpus != nil {
+			corpus.addAll(text)
+		}
+		for _, hostname := range findHostnames(text, roots) {
+			input, err := NewInputWithSuffixList(hostname, m.Options.SuffixList)
+			if err != nil || input.Sub == "" {
+				continue
+			}
+			// hostnames already present in inputs are not counted as collapsed duplicates
+			if _, ok := m.seenInputs[input.Hostname()]; ok {
+				continue
+			}
+			m.seenInputs[input.Hostname()] = input
+			input.tokenizer = m.Options.Tokenizer
+			m.addSeries(input)
+			m.Inputs = append(m.Inputs, input)
+			m.Options.Domains = append(m.Options.Domains, hostname)
+			added++
+		}
+	}
+	gologger.Info().Msgf("Found %d new subdomains in %d enrich sources", added, len(files))
+	return nil
+}
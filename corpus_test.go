@@ -0,0 +1,57 @@
+package alterx
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindHostnames(t *testing.T) {
+	roots := map[string]struct{}{"example.com": {}}
+	text := `fetch("https://API.example.com/v1");var u='//cdn-eu.static.example.com:8443/app.js';
+	mail admin@example.com at mail.example.com or -internal.example.com, not example.org or api.example.com.evil.net`
+	require.Equal(t, []string{"api.example.com", "cdn-eu.static.example.com", "mail.example.com", "internal.example.com"}, findHostnames(text, roots))
+}
+
+func TestCorpusText(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "vpn.example.com"},
+		DNSNames:     []string{"sso.example.com", "*.corp.example.com"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	bin := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+
+	roots := map[string]struct{}{"example.com": {}}
+	require.Empty(t, findHostnames(string(bin), roots))
+	require.Equal(t, []string{"vpn.example.com", "sso.example.com", "corp.example.com"}, findHostnames(corpusText(bin), roots))
+}
+
+func TestCorpusFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, "js"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "js", "app.js"), []byte("x"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "index.html"), []byte("x"), 0644))
+
+	files, err := corpusFiles([]string{tmpDir})
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{filepath.Join(tmpDir, "js", "app.js"), filepath.Join(tmpDir, "index.html")}, files)
+
+	_, err = corpusFiles([]string{filepath.Join(tmpDir, "missing")})
+	require.Error(t, err)
+}
@@ -0,0 +1,178 @@
+package alterx
+
+import (
+	"sort"
+	"strings"
+)
+
+const (
+	// DefaultEnrichKey is payload enriched words are added to
+	DefaultEnrichKey = "word"
+	// DefaultEnrichMinLength is minimum length of enriched words
+	DefaultEnrichMinLength = 3
+	// DefaultCorpusMinCount is minimum number of occurrences of words taken from whole text of enrich sources
+	DefaultCorpusMinCount = 3
+	// randomHexLength is minimum length of hex tokens considered random (ex: hashes)
+	randomHexLength = 10
+)
+
+// corpusStopWords are common words of code and markup never taken from text of enrich sources
+var corpusStopWords = toSet(strings.Fields(`
+	function var let const return new this typeof instanceof void delete null undefined true false
+	if else for while do break continue switch case default try catch finally throw class extends
+	super import export from async await yield static get set window document prototype constructor
+	object string number boolean array length push call apply bind console error
+	addeventlistener removeeventlistener getelementbyid queryselector innerhtml
+	http https www html head body div span script style link meta href src type text json xml css
+	width height value name data item index key use strict the and with that not are was
+`))
+
+// Enrichment contains thresholds of words extracted from inputs by enrich
+type Enrichment struct {
+	// Key is payload enriched words are added to (default: word)
+	// payload is created if it does not exist (ex: enriched => {{enriched}})
+	Key string `yaml:"key"`
+	// MinCount is minimum number of inputs a word must appear in (default: 1)
+	MinCount int `yaml:"min-count"`
+	// MinLength is minimum length of words (default: 3)
+	MinLength int `yaml:"min-length"`
+	// MaxWords limits enriched words to most frequent ones (0 = no limit)
+	MaxWords int `yaml:"max-words"`
+	// KeepRandom when true, random looking words (ex: hashes, ids) are not filtered
+	KeepRandom bool `yaml:"keep-random"`
+	// CorpusWords when true, words are also taken from whole text of enrich sources
+	// instead of only subdomains found in them. such words are filtered by a stoplist
+	// and must occur at least DefaultCorpusMinCount times (or MinCount if higher)
+	CorpusWords bool `yaml:"corpus-words"`
+}
+
+// withDefaults returns copy of enrichment with defaults of unset thresholds
+func (e *Enrichment) withDefaults() *Enrichment {
+	enrichment := Enrichment{}
+	if e != nil {
+		enrichment = *e
+	}
+	if enrichment.Key == "" {
+		enrichment.Key = DefaultEnrichKey
+	}
+	if enrichment.MinCount <= 0 {
+		enrichment.MinCount = 1
+	}
+	if enrichment.MinLength <= 0 {
+		enrichment.MinLength = DefaultEnrichMinLength
+	}
+	return &enrichment
+}
+
+// wordCounter counts number of texts every extracted word appears in
+type wordCounter struct {
+	counts map[string]int
+	order  []string // words in order of first occurrence
+}
+
+func newWordCounter() *wordCounter {
+	return &wordCounter{counts: map[string]int{}}
+}
+
+// add extracts words from text and counts each of them once
+func (c *wordCounter) add(text string) {
+	c.count(text, true)
+}
+
+// addAll extracts words from text and counts every occurrence of them
+func (c *wordCounter) addAll(text string) {
+	c.count(text, false)
+}
+
+func (c *wordCounter) count(text string, once bool) {
+	seen := map[string]struct{}{}
+	add := func(word string) {
+		if _, ok := seen[word]; ok && once {
+			return
+		}
+		seen[word] = struct{}{}
+		if c.counts[word] == 0 {
+			c.order = append(c.order, word)
+		}
+		c.counts[word]++
+	}
+	for _, token := range extractWords.FindAllString(strings.ToLower(text), -1) {
+		add(token)
+		// letters of alphanumeric tokens are also words (ex: dev for dev01)
+		for _, word := range extractWordsOnly.FindAllString(token, -1) {
+			if word != token {
+				add(word)
+			}
+		}
+	}
+}
+
+// words returns words passing thresholds of enrichment ranked by frequency
+// words present in exclude (ex: labels of roots) are skipped
+func (c *wordCounter) words(e *Enrichment, exclude map[string]struct{}) []string {
+	var words []string
+	for _, word := range c.order {
+		// numbers are extrapolated as numeric series instead
+		if c.counts[word] < e.MinCount || len(word) < e.MinLength || isNumber(word) {
+			continue
+		}
+		if _, ok := exclude[word]; ok {
+			continue
+		}
+		if !e.KeepRandom && isRandomToken(word) {
+			continue
+		}
+		words = append(words, word)
+	}
+	sort.SliceStable(words, func(i, j int) bool {
+		return c.counts[words[i]] > c.counts[words[j]]
+	})
+	if e.MaxWords > 0 && len(words) > e.MaxWords {
+		words = words[:e.MaxWords]
+	}
+	return words
+}
+
+// isRandomToken returns true if token looks like a hash, id or other random value
+// rather than a word (ex: a1b2c3, deadbeef42, xkcdqz)
+func isRandomToken(token string) bool {
+	var letters, vowels, digits, transitions int
+	hex := true
+	for i := 0; i < len(token); i++ {
+		c := token[i]
+		switch {
+		case isDigit(c):
+			digits++
+		case strings.IndexByte("aeiouy", c) >= 0:
+			letters++
+			vowels++
+		default:
+			letters++
+		}
+		if !isDigit(c) && (c < 'a' || c > 'f') {
+			hex = false
+		}
+		if i > 0 && isDigit(c) != isDigit(token[i-1]) {
+			transitions++
+		}
+	}
+	switch {
+	case transitions >= 3:
+		// letters and digits are interleaved
+		return true
+	case hex && digits > 0 && letters > 0 && len(token) >= randomHexLength:
+		return true
+	case letters >= 5 && vowels == 0:
+		return true
+	}
+	return false
+}
+
+// toSet returns set of values
+func toSet(values []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(values))
+	for _, v := range values {
+		set[v] = struct{}{}
+	}
+	return set
+}
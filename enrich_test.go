@@ -0,0 +1,52 @@
+package alterx
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsRandomToken(t *testing.T) {
+	for _, v := range []string{"a1b2c3", "x9k2m7", "deadbeef42", "0af3c9d2e1", "xkcdqz"} {
+		require.True(t, isRandomToken(v), v)
+	}
+	for _, v := range []string{"api", "prod1", "web01", "s3", "staging", "cdn", "k8s", "cafe2024"} {
+		require.False(t, isRandomToken(v), v)
+	}
+}
+
+func TestWordCounter(t *testing.T) {
+	counter := newWordCounter()
+	for _, v := range []string{"api-prod api", "API-dev01", "web-prod 1234", "a1b2c3-cdn"} {
+		counter.add(v)
+	}
+	require.Equal(t, 2, counter.counts["api"], "words are counted once per text")
+
+	testcases := []struct {
+		name       string
+		enrichment *Enrichment
+		expected   []string
+	}{
+		{name: "defaults", enrichment: &Enrichment{}, expected: []string{"api", "prod", "dev01", "dev", "web", "cdn"}},
+		{name: "min count", enrichment: &Enrichment{MinCount: 2}, expected: []string{"api", "prod"}},
+		{name: "min length", enrichment: &Enrichment{MinLength: 4}, expected: []string{"prod", "dev01"}},
+		{name: "max words", enrichment: &Enrichment{MaxWords: 1}, expected: []string{"api"}},
+		{name: "keep random", enrichment: &Enrichment{KeepRandom: true, MinLength: 6}, expected: []string{"a1b2c3"}},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, counter.words(tc.enrichment.withDefaults(), nil))
+		})
+	}
+
+	t.Run("exclude", func(t *testing.T) {
+		require.Equal(t, []string{"prod", "dev01", "dev", "web"}, counter.words((&Enrichment{}).withDefaults(), toSet([]string{"api", "cdn"})))
+	})
+
+	t.Run("occurrences", func(t *testing.T) {
+		counter := newWordCounter()
+		counter.addAll("function checkout() { return checkout(cart) } // checkout")
+		require.Equal(t, 3, counter.counts["checkout"])
+		require.Equal(t, 1, counter.counts["cart"])
+	})
+}
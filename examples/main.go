@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"math"
 	"os"
 
@@ -20,5 +21,7 @@ func main() {
 	if err != nil {
 		gologger.Fatal().Msg(err.Error())
 	}
-	m.ExecuteWithWriter(os.Stdout)
+	if err := m.ExecuteWithWriter(context.Background(), os.Stdout); err != nil {
+		gologger.Fatal().Msg(err.Error())
+	}
 }
@@ -0,0 +1,201 @@
+package alterx
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// FilterSeparator separates a variable from its filters and filters from each other (ex: {{sub|upper}})
+	FilterSeparator = "|"
+	// FilterArgSeparator separates filter name from its arguments (ex: {{number|pad:3}})
+	FilterArgSeparator = ":"
+)
+
+// Filter is a transform that can be applied to the value of a placeholder
+// using pipe syntax inside patterns. ex: {{sub|upper}}, {{word|trim:-}},
+// {{sub|replace:-:}} or {{number|pad:3}}
+type Filter struct {
+	// MinArgs is minimum number of arguments required by filter
+	MinArgs int
+	// MaxArgs is maximum number of arguments accepted by filter
+	MaxArgs int
+	// Apply transforms value using given arguments
+	Apply func(value string, args []string) (string, error)
+}
+
+// filterMutex guards filterRegistry against concurrent registration
+var filterMutex sync.RWMutex
+
+// filterRegistry contains all available filters keyed by name
+var filterRegistry = map[string]Filter{
+	"upper": {Apply: func(value string, _ []string) (string, error) {
+		return strings.ToUpper(value), nil
+	}},
+	"lower": {Apply: func(value string, _ []string) (string, error) {
+		return strings.ToLower(value), nil
+	}},
+	"title": {Apply: func(value string, _ []string) (string, error) {
+		r, size := utf8.DecodeRuneInString(value)
+		if r == utf8.RuneError {
+			return value, nil
+		}
+		return string(unicode.ToUpper(r)) + value[size:], nil
+	}},
+	// trim strips given characters (default: -_.) from both ends of value
+	"trim": {MaxArgs: 1, Apply: func(value string, args []string) (string, error) {
+		cutset := "-_."
+		if len(args) > 0 && args[0] != "" {
+			cutset = args[0]
+		}
+		return strings.Trim(value, cutset), nil
+	}},
+	// replace replaces all occurrences of first argument with second argument
+	"replace": {MinArgs: 2, MaxArgs: 2, Apply: func(value string, args []string) (string, error) {
+		if args[0] == "" {
+			return "", fmt.Errorf("replace: old value cannot be empty")
+		}
+		return strings.ReplaceAll(value, args[0], args[1]), nil
+	}},
+	// pad left pads value to given width using given character (default: 0)
+	"pad": {MinArgs: 1, MaxArgs: 2, Apply: func(value string, args []string) (string, error) {
+		width, err := strconv.Atoi(args[0])
+		if err != nil || width < 0 {
+			return "", fmt.Errorf("pad: invalid width '%v'", args[0])
+		}
+		padChar := "0"
+		if len(args) > 1 {
+			if utf8.RuneCountInString(args[1]) != 1 {
+				return "", fmt.Errorf("pad: padding must be a single character got '%v'", args[1])
+			}
+			padChar = args[1]
+		}
+		if count := width - utf8.RuneCountInString(value); count > 0 {
+			return strings.Repeat(padChar, count) + value, nil
+		}
+		return value, nil
+	}},
+	// truncate keeps at most given number of characters from value
+	"truncate": {MinArgs: 1, MaxArgs: 1, Apply: func(value string, args []string) (string, error) {
+		size, err := strconv.Atoi(args[0])
+		if err != nil || size < 0 {
+			return "", fmt.Errorf("truncate: invalid length '%v'", args[0])
+		}
+		runes := []rune(value)
+		if len(runes) > size {
+			return string(runes[:size]), nil
+		}
+		return value, nil
+	}},
+	"reverse": {Apply: func(value string, _ []string) (string, error) {
+		runes := []rune(value)
+		for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+			runes[i], runes[j] = runes[j], runes[i]
+		}
+		return string(runes), nil
+	}},
+	// first returns first token of value split by given separator (default: -)
+	"first": {MaxArgs: 1, Apply: func(value string, args []string) (string, error) {
+		tokens := strings.Split(value, tokenSeparator(args))
+		return tokens[0], nil
+	}},
+	// last returns last token of value split by given separator (default: -)
+	"last": {MaxArgs: 1, Apply: func(value string, args []string) (string, error) {
+		tokens := strings.Split(value, tokenSeparator(args))
+		return tokens[len(tokens)-1], nil
+	}},
+}
+
+// tokenSeparator returns separator used by first/last filters
+func tokenSeparator(args []string) string {
+	if len(args) > 0 && args[0] != "" {
+		return args[0]
+	}
+	return "-"
+}
+
+// RegisterFilter registers a custom filter that can be used in patterns
+// registering a filter with existing name overwrites it. it is safe to call
+// concurrently but patterns compiled before registration are not affected
+func RegisterFilter(name string, filter Filter) error {
+	if name == "" || strings.ContainsAny(name, FilterSeparator+FilterArgSeparator+"{}") {
+		return fmt.Errorf("invalid filter name '%v'", name)
+	}
+	if filter.Apply == nil {
+		return fmt.Errorf("filter '%v' does not have an apply function", name)
+	}
+	filterMutex.Lock()
+	defer filterMutex.Unlock()
+	filterRegistry[name] = filter
+	return nil
+}
+
+// filterCall is a single invocation of filter with its arguments
+type filterCall struct {
+	name   string
+	filter Filter
+	args   []string
+}
+
+// filterChain is list of filters applied in order from left to right
+type filterChain []filterCall
+
+// apply runs value through all filters of chain
+func (c filterChain) apply(value string) (string, error) {
+	var err error
+	for _, call := range c {
+		if value, err = call.filter.Apply(value, call.args); err != nil {
+			return "", err
+		}
+	}
+	return value, nil
+}
+
+// parsePlaceholder splits placeholder into variable name and filter chain
+// ex: `number|pad:3` => `number`, [pad(3)]
+func parsePlaceholder(tag string) (string, filterChain, error) {
+	parts := strings.Split(tag, FilterSeparator)
+	name := strings.TrimSpace(parts[0])
+	if name == "" {
+		return "", nil, fmt.Errorf("placeholder `%v` is missing variable name", tag)
+	}
+	var chain filterChain
+	for _, expr := range parts[1:] {
+		args := strings.Split(expr, FilterArgSeparator)
+		filterName := strings.TrimSpace(args[0])
+		filterMutex.RLock()
+		filter, ok := filterRegistry[filterName]
+		filterMutex.RUnlock()
+		if !ok {
+			return "", nil, fmt.Errorf("unknown filter `%v` in placeholder `%v`", filterName, tag)
+		}
+		args = args[1:]
+		if len(args) < filter.MinArgs || len(args) > filter.MaxArgs {
+			return "", nil, fmt.Errorf("filter `%v` in placeholder `%v` expects %v to %v arguments but got %v", filterName, tag, filter.MinArgs, filter.MaxArgs, len(args))
+		}
+		chain = append(chain, filterCall{name: filterName, filter: filter, args: args})
+	}
+	return name, chain, nil
+}
+
+// validateFilters parses and dry runs all filter chains used in template
+func validateFilters(template string) error {
+	for _, v := range placeholderRegex.FindAllStringSubmatch(template, -1) {
+		if !strings.Contains(v[1], FilterSeparator) {
+			continue
+		}
+		_, chain, err := parsePlaceholder(v[1])
+		if err != nil {
+			return err
+		}
+		// arguments are only validated when filter is applied
+		if _, err := chain.apply("temp"); err != nil {
+			return fmt.Errorf("invalid filter in placeholder `%v`: %w", v[0], err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package alterx
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilters(t *testing.T) {
+	testcases := []struct {
+		placeholder string
+		value       string
+		expected    string
+	}{
+		{placeholder: "sub|upper", value: "api", expected: "API"},
+		{placeholder: "sub|lower", value: "API", expected: "api"},
+		{placeholder: "sub|title", value: "api", expected: "Api"},
+		{placeholder: "word|trim", value: "-api_", expected: "api"},
+		{placeholder: "word|trim:-", value: "--api-", expected: "api"},
+		{placeholder: "sub|replace:-:", value: "api-dev-1", expected: "apidev1"},
+		{placeholder: "sub|replace:-:.", value: "api-dev", expected: "api.dev"},
+		{placeholder: "number|pad:3", value: "7", expected: "007"},
+		{placeholder: "number|pad:3:x", value: "7", expected: "xx7"},
+		{placeholder: "number|pad:2", value: "123", expected: "123"},
+		{placeholder: "word|truncate:3", value: "staging", expected: "sta"},
+		{placeholder: "word|reverse", value: "api", expected: "ipa"},
+		{placeholder: "sub|first", value: "api-gateway-eu", expected: "api"},
+		{placeholder: "sub|last", value: "api-gateway-eu", expected: "eu"},
+		{placeholder: "sub|first:_", value: "api_gateway", expected: "api"},
+		{placeholder: "sub|last|upper", value: "api-gateway", expected: "GATEWAY"},
+		{placeholder: "sub|replace:-:|truncate:5|upper", value: "api-gateway", expected: "APIGA"},
+	}
+	for _, tc := range testcases {
+		name, chain, err := parsePlaceholder(tc.placeholder)
+		require.NoError(t, err, tc.placeholder)
+		require.NotEmpty(t, name)
+		got, err := chain.apply(tc.value)
+		require.NoError(t, err, tc.placeholder)
+		require.Equal(t, tc.expected, got, tc.placeholder)
+	}
+}
+
+func TestParsePlaceholderErrors(t *testing.T) {
+	testcases := []string{
+		"sub|unknown",
+		"|upper",
+		"sub|replace:-",
+		"sub|upper:1",
+		"number|pad",
+	}
+	for _, v := range testcases {
+		_, _, err := parsePlaceholder(v)
+		require.Error(t, err, v)
+	}
+}
+
+func TestValidateFilters(t *testing.T) {
+	t.Run("valid filters", func(t *testing.T) {
+		require.NoError(t, validateFilters("{{sub|upper}}-{{number|pad:3}}.{{suffix}}"))
+		require.NoError(t, validateFilters("{{sub}}.{{suffix}}"))
+	})
+
+	t.Run("invalid argument type", func(t *testing.T) {
+		require.Error(t, validateFilters("{{number|pad:abc}}.{{suffix}}"))
+		require.Error(t, validateFilters("{{word|truncate:-1}}.{{suffix}}"))
+	})
+
+	t.Run("unknown filter", func(t *testing.T) {
+		require.Error(t, validateFilters("{{sub|explode}}.{{suffix}}"))
+	})
+}
+
+func TestRegisterFilter(t *testing.T) {
+	err := RegisterFilter("double", Filter{Apply: func(value string, _ []string) (string, error) {
+		return value + value, nil
+	}})
+	require.NoError(t, err)
+	defer delete(filterRegistry, "double")
+
+	require.Equal(t, "apiapi.example.com", Replace("{{sub|double}}.example.com", map[string]interface{}{"sub": "api"}))
+
+	require.Error(t, RegisterFilter("in|valid", Filter{Apply: func(value string, _ []string) (string, error) { return value, nil }}))
+	require.Error(t, RegisterFilter("noop", Filter{}))
+}
+
+func TestRegisterFilterConcurrently(t *testing.T) {
+	defer delete(filterRegistry, "noop")
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			require.NoError(t, RegisterFilter("noop", Filter{Apply: func(value string, _ []string) (string, error) {
+				return value, nil
+			}}))
+		}()
+		go func() {
+			defer wg.Done()
+			require.NoError(t, validateFilters("{{sub|upper}}.{{suffix}}"))
+		}()
+	}
+	wg.Wait()
+}
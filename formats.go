@@ -0,0 +1,214 @@
+package alterx
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Input formats
+const (
+	// InputFormatPlain is list of domains separated by whitespace, each optionally
+	// followed by annotations (ex: api.example.com env=prod team=payments)
+	InputFormatPlain = "plain"
+	// InputFormatJSONL is one json object per line (ex: output of subfinder or dnsx with -json)
+	InputFormatJSONL = "jsonl"
+	// InputFormatCSV is csv with header row
+	InputFormatCSV = "csv"
+)
+
+// AnnotationSeparator separates name and value of annotations in plain inputs (ex: env=prod)
+const AnnotationSeparator = "="
+
+// InputFormats contains all available input formats
+var InputFormats = []string{InputFormatPlain, InputFormatJSONL, InputFormatCSV}
+
+// DefaultDomainFields are fields of jsonl inputs containing domain in order of preference
+var DefaultDomainFields = []string{"host", "domain", "name", "input"}
+
+// invalidVarChars matches characters that can't be used in variable names
+var invalidVarChars = regexp.MustCompile(`[^a-zA-Z0-9_]+`)
+
+// annotationRegex matches annotations of plain inputs
+var annotationRegex = regexp.MustCompile(`^([a-zA-Z0-9_-]+)` + AnnotationSeparator + `(.*)$`)
+
+// InputRecord is an input domain along with its extra variables
+type InputRecord struct {
+	Domain string
+	Vars   map[string]string
+}
+
+// InputParser parses lines of inputs in given format into records
+// it is stateful for formats with header (ex: csv) so lines must be parsed in order
+type InputParser struct {
+	// Format is format of input lines
+	Format string
+	// Column is field containing domain, name of json field or csv column (or 1-based csv column index)
+	// if empty, DefaultDomainFields are used for jsonl and first column for csv
+	Column string
+
+	header []string
+}
+
+// NewInputParser creates parser of inputs in given format
+func NewInputParser(format string, column string) (*InputParser, error) {
+	if format == "" {
+		format = InputFormatPlain
+	}
+	valid := false
+	for _, v := range InputFormats {
+		valid = valid || v == format
+	}
+	if !valid {
+		return nil, fmt.Errorf("unknown input format '%s': supported values are %s", format, strings.Join(InputFormats, ","))
+	}
+	return &InputParser{Format: format, Column: column}, nil
+}
+
+// Parse parses a single line of input and returns records present in it
+// lines starting with `#` are ignored in all formats and trailing comments are removed from plain inputs
+func (p *InputParser) Parse(line string) ([]*InputRecord, error) {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, inputCommentPrefix) {
+		return nil, nil
+	}
+	if p.Format == InputFormatPlain {
+		line = stripComment(line)
+	}
+	switch p.Format {
+	case InputFormatJSONL:
+		return p.parseJSON(line)
+	case InputFormatCSV:
+		return p.parseCSV(line)
+	default:
+		return parsePlain(line)
+	}
+}
+
+// parsePlain parses whitespace separated domains where annotations are added
+// as variables of preceding domain
+func parsePlain(line string) ([]*InputRecord, error) {
+	var records []*InputRecord
+	for _, v := range strings.Fields(line) {
+		match := annotationRegex.FindStringSubmatch(v)
+		if match == nil {
+			records = append(records, &InputRecord{Domain: v})
+			continue
+		}
+		if len(records) == 0 {
+			return nil, fmt.Errorf("annotation `%v` does not follow any domain", v)
+		}
+		record := records[len(records)-1]
+		if record.Vars == nil {
+			record.Vars = map[string]string{}
+		}
+		if match[2] != "" {
+			record.Vars[varName(match[1])] = match[2]
+		}
+	}
+	return records, nil
+}
+
+// parseJSON parses json object and uses all other scalar or list fields as variables
+func (p *InputParser) parseJSON(line string) ([]*InputRecord, error) {
+	var fields map[string]interface{}
+	if err := json.Unmarshal([]byte(line), &fields); err != nil {
+		return nil, fmt.Errorf("invalid json input: %w", err)
+	}
+	domainFields := DefaultDomainFields
+	if p.Column != "" {
+		domainFields = []string{p.Column}
+	}
+	record := &InputRecord{Vars: map[string]string{}}
+	var domainField string
+	for _, v := range domainFields {
+		if value, ok := fields[v].(string); ok && value != "" {
+			record.Domain, domainField = value, v
+			break
+		}
+	}
+	if record.Domain == "" {
+		return nil, fmt.Errorf("json input does not contain any of %s fields", strings.Join(domainFields, ","))
+	}
+	for k, v := range fields {
+		if k == domainField {
+			continue
+		}
+		if value, ok := formatVar(v); ok {
+			record.Vars[varName(k)] = value
+		}
+	}
+	return []*InputRecord{record}, nil
+}
+
+// parseCSV parses csv row, first row is used as header containing names of variables
+func (p *InputParser) parseCSV(line string) ([]*InputRecord, error) {
+	row, err := csv.NewReader(strings.NewReader(line)).Read()
+	if err != nil {
+		return nil, fmt.Errorf("invalid csv input: %w", err)
+	}
+	if p.header == nil {
+		p.header = row
+		if p.domainColumn() < 0 {
+			return nil, fmt.Errorf("csv header does not contain column `%v`", p.Column)
+		}
+		return nil, nil
+	}
+	column := p.domainColumn()
+	if column >= len(row) {
+		return nil, fmt.Errorf("csv row has %v columns but domain is in column %v", len(row), column+1)
+	}
+	record := &InputRecord{Domain: strings.TrimSpace(row[column]), Vars: map[string]string{}}
+	for i, v := range row {
+		if i != column && i < len(p.header) && v != "" {
+			record.Vars[varName(p.header[i])] = v
+		}
+	}
+	return []*InputRecord{record}, nil
+}
+
+// domainColumn returns index of csv column containing domain or -1 if not found
+func (p *InputParser) domainColumn() int {
+	if p.Column == "" {
+		return 0
+	}
+	for i, v := range p.header {
+		if strings.TrimSpace(v) == p.Column {
+			return i
+		}
+	}
+	if index, err := strconv.Atoi(p.Column); err == nil && index > 0 {
+		return index - 1
+	}
+	return -1
+}
+
+// formatVar formats json value as variable, lists are joined using comma
+// and objects are ignored
+func formatVar(value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		return v, v != ""
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case bool:
+		return strconv.FormatBool(v), true
+	case []interface{}:
+		var values []string
+		for _, item := range v {
+			if value, ok := formatVar(item); ok {
+				values = append(values, value)
+			}
+		}
+		return strings.Join(values, ","), len(values) > 0
+	}
+	return "", false
+}
+
+// varName converts field name to valid variable name (ex: status-code => status_code)
+func varName(field string) string {
+	return invalidVarChars.ReplaceAllString(strings.TrimSpace(field), "_")
+}
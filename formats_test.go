@@ -0,0 +1,118 @@
+package alterx
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInputParserPlain(t *testing.T) {
+	p, err := NewInputParser("", "")
+	require.NoError(t, err)
+	records, err := p.Parse(" api.example.com  dev.example.com ")
+	require.NoError(t, err)
+	require.Equal(t, []*InputRecord{{Domain: "api.example.com"}, {Domain: "dev.example.com"}}, records)
+
+	records, err = p.Parse("")
+	require.NoError(t, err)
+	require.Empty(t, records)
+}
+
+func TestInputParserAnnotations(t *testing.T) {
+	p, err := NewInputParser(InputFormatPlain, "")
+	require.NoError(t, err)
+
+	records, err := p.Parse("api.example.com env=prod team-name=payments dev.example.com env= https://www.example.com/?id=1")
+	require.NoError(t, err)
+	require.Equal(t, []*InputRecord{
+		{Domain: "api.example.com", Vars: map[string]string{"env": "prod", "team_name": "payments"}},
+		{Domain: "dev.example.com", Vars: map[string]string{}},
+		{Domain: "https://www.example.com/?id=1"},
+	}, records)
+
+	_, err = p.Parse("env=prod api.example.com")
+	require.Error(t, err)
+}
+
+func TestInputParserJSONL(t *testing.T) {
+	p, err := NewInputParser(InputFormatJSONL, "")
+	require.NoError(t, err)
+
+	t.Run("subfinder", func(t *testing.T) {
+		records, err := p.Parse(`{"host":"api.example.com","input":"example.com","source":"crtsh"}`)
+		require.NoError(t, err)
+		require.Len(t, records, 1)
+		require.Equal(t, "api.example.com", records[0].Domain)
+		require.Equal(t, map[string]string{"input": "example.com", "source": "crtsh"}, records[0].Vars)
+	})
+
+	t.Run("dnsx", func(t *testing.T) {
+		records, err := p.Parse(`{"host":"api.example.com","a":["1.1.1.1","1.0.0.1"],"status-code":"NOERROR","ttl":300,"wildcard":false,"resolver":{"addr":"8.8.8.8"}}`)
+		require.NoError(t, err)
+		require.Len(t, records, 1)
+		require.Equal(t, map[string]string{"a": "1.1.1.1,1.0.0.1", "status_code": "NOERROR", "ttl": "300", "wildcard": "false"}, records[0].Vars)
+	})
+
+	t.Run("fallback fields", func(t *testing.T) {
+		records, err := p.Parse(`{"input":"dev.example.com"}`)
+		require.NoError(t, err)
+		require.Equal(t, "dev.example.com", records[0].Domain)
+		require.Empty(t, records[0].Vars)
+	})
+
+	t.Run("column", func(t *testing.T) {
+		p, err := NewInputParser(InputFormatJSONL, "fqdn")
+		require.NoError(t, err)
+		records, err := p.Parse(`{"fqdn":"api.example.com","host":"1.1.1.1"}`)
+		require.NoError(t, err)
+		require.Equal(t, "api.example.com", records[0].Domain)
+		require.Equal(t, map[string]string{"host": "1.1.1.1"}, records[0].Vars)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := p.Parse(`api.example.com`)
+		require.Error(t, err)
+		_, err = p.Parse(`{"source":"crtsh"}`)
+		require.Error(t, err)
+	})
+}
+
+func TestInputParserCSV(t *testing.T) {
+	t.Run("named column", func(t *testing.T) {
+		p, err := NewInputParser(InputFormatCSV, "subdomain")
+		require.NoError(t, err)
+		records, err := p.Parse("ip,subdomain,Cloud Provider")
+		require.NoError(t, err)
+		require.Empty(t, records)
+
+		records, err = p.Parse(`1.1.1.1,api.example.com,"aws, us-east-1"`)
+		require.NoError(t, err)
+		require.Equal(t, []*InputRecord{{Domain: "api.example.com", Vars: map[string]string{"ip": "1.1.1.1", "Cloud_Provider": "aws, us-east-1"}}}, records)
+
+		_, err = p.Parse("1.1.1.1")
+		require.Error(t, err)
+	})
+
+	t.Run("column index", func(t *testing.T) {
+		p, err := NewInputParser(InputFormatCSV, "2")
+		require.NoError(t, err)
+		_, err = p.Parse("env,host")
+		require.NoError(t, err)
+		records, err := p.Parse("prod,api.example.com")
+		require.NoError(t, err)
+		require.Equal(t, "api.example.com", records[0].Domain)
+		require.Equal(t, map[string]string{"env": "prod"}, records[0].Vars)
+	})
+
+	t.Run("missing column", func(t *testing.T) {
+		p, err := NewInputParser(InputFormatCSV, "fqdn")
+		require.NoError(t, err)
+		_, err = p.Parse("env,host")
+		require.Error(t, err)
+	})
+}
+
+func TestInputParserInvalidFormat(t *testing.T) {
+	_, err := NewInputParser("xml", "")
+	require.Error(t, err)
+}
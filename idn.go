@@ -0,0 +1,88 @@
+package alterx
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/idna"
+)
+
+// Output forms of internationalized domain names
+const (
+	// IDNASCII emits internationalized labels in punycode form (ex: xn--bcher-kva.example.com)
+	IDNASCII = "ascii"
+	// IDNUnicode emits internationalized labels in unicode form (ex: bücher.example.com)
+	IDNUnicode = "unicode"
+)
+
+// punycodePrefix is ACE prefix of punycode encoded labels
+const punycodePrefix = "xn--"
+
+// IDNForms contains all available output forms of internationalized domain names
+var IDNForms = []string{IDNASCII, IDNUnicode}
+
+// validateIDNForm checks if given output form is supported
+func validateIDNForm(form string) error {
+	for _, v := range IDNForms {
+		if v == form {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown idn output form '%s': supported values are %s", form, strings.Join(IDNForms, ","))
+}
+
+// isIDN returns true if name contains unicode or punycode encoded labels
+func isIDN(name string) bool {
+	if hasNonASCII(name) {
+		return true
+	}
+	for _, label := range strings.Split(name, ".") {
+		if strings.HasPrefix(strings.ToLower(label), punycodePrefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasNonASCII returns true if value contains non ascii characters
+func hasNonASCII(value string) bool {
+	for i := 0; i < len(value); i++ {
+		if value[i] >= utf8.RuneSelf {
+			return true
+		}
+	}
+	return false
+}
+
+// toASCIIName converts unicode labels of name to punycode form
+// ascii labels are kept as is and are left to be checked by validation
+func toASCIIName(name string) (string, error) {
+	labels := strings.Split(name, ".")
+	for i, label := range labels {
+		if !hasNonASCII(label) {
+			continue
+		}
+		ascii, err := idna.Lookup.ToASCII(label)
+		if err != nil {
+			return "", fmt.Errorf("invalid internationalized label `%v`: %w", label, err)
+		}
+		labels[i] = ascii
+	}
+	return strings.Join(labels, "."), nil
+}
+
+// toUnicodeName converts punycode labels of name to unicode form
+// labels that can't be decoded are kept as is
+func toUnicodeName(name string) string {
+	labels := strings.Split(name, ".")
+	for i, label := range labels {
+		if !strings.HasPrefix(strings.ToLower(label), punycodePrefix) {
+			continue
+		}
+		if unicode, err := idna.Lookup.ToUnicode(label); err == nil && unicode != "" {
+			labels[i] = unicode
+		}
+	}
+	return strings.Join(labels, ".")
+}
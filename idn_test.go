@@ -0,0 +1,24 @@
+package alterx
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsIDN(t *testing.T) {
+	require.True(t, isIDN("bücher.example.com"))
+	require.True(t, isIDN("xn--bcher-kva.example.com"))
+	require.True(t, isIDN("api.XN--BCHER-KVA.com"))
+	require.False(t, isIDN("api.example.com"))
+}
+
+func TestIDNConversion(t *testing.T) {
+	got, err := toASCIIName("api-bücher.Bücher.example.com")
+	require.NoError(t, err)
+	require.Equal(t, "xn--api-bcher-u9a.xn--bcher-kva.example.com", got)
+	require.Equal(t, "api-bücher.bücher.example.com", toUnicodeName(got))
+
+	// invalid punycode labels are kept as is
+	require.Equal(t, "xn--.example.com", toUnicodeName("xn--.example.com"))
+}
@@ -2,6 +2,7 @@ package alterx
 
 import (
 	"fmt"
+	"regexp"
 	"strconv"
 	"strings"
 
@@ -10,6 +11,72 @@ import (
 	"golang.org/x/net/publicsuffix"
 )
 
+const (
+	// WildcardSlot is placeholder of template inputs filled with payload values
+	WildcardSlot = "*"
+	// DefaultWildcardPayload is payload used to fill wildcards of template inputs
+	DefaultWildcardPayload = "word"
+)
+
+// reservedVarRegex matches names of variables derived from domain
+// extra variables of input with these names are ignored even if they are missing for input
+var reservedVarRegex = regexp.MustCompile(`^(tld|etld|sld|root|sub|suffix|sub_last|subs|depth|sub[0-9]+|suffix[0-9]+|subtok[0-9]+|subtok_last|subtok_sep)$`)
+
+// DefaultTokenSeparators are characters splitting labels into tokens
+const DefaultTokenSeparators = "-_"
+
+// DefaultTokenizer is tokenizer used for inputs without a configured tokenizer
+var DefaultTokenizer = &Tokenizer{Separators: DefaultTokenSeparators}
+
+// Tokenizer splits labels into tokens exposed as {{subtokN}} variables
+// ex: api-gateway-eu2 => api, gateway, eu, 2
+type Tokenizer struct {
+	// Separators are characters splitting tokens (If empty, DefaultTokenSeparators are used)
+	Separators string `yaml:"separators"`
+	// KeepDigits when true, labels are not split on letter/digit boundaries (ex: eu2)
+	KeepDigits bool `yaml:"keep-digits"`
+}
+
+// Tokenize splits label into tokens and returns them along with first separator
+// present in label (empty if label has no separator)
+func (t *Tokenizer) Tokenize(label string) ([]string, string) {
+	separators := t.Separators
+	if separators == "" {
+		separators = DefaultTokenSeparators
+	}
+	var separator string
+	if i := strings.IndexAny(label, separators); i >= 0 {
+		separator = label[i : i+1]
+	}
+	parts := tokenize(label, []string{separators})
+	if t.KeepDigits {
+		return parts, separator
+	}
+	var tokens []string
+	for _, part := range parts {
+		tokens = append(tokens, splitDigits(part)...)
+	}
+	return tokens, separator
+}
+
+// splitDigits splits value on letter/digit boundaries (ex: eu2 => eu, 2)
+func splitDigits(value string) []string {
+	var tokens []string
+	start := 0
+	for i := 1; i < len(value); i++ {
+		if isDigit(value[i]) != isDigit(value[i-1]) {
+			tokens = append(tokens, value[start:i])
+			start = i
+		}
+	}
+	return append(tokens, value[start:])
+}
+
+// isDigit returns true if character is an ascii digit
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
 // Input contains parsed/evaluated data of a URL
 type Input struct {
 	TLD        string   // only TLD (right most part of subdomain) ex: `.uk`
@@ -19,6 +86,17 @@ type Input struct {
 	Sub        string   // Sub or LeftMost prefix of subdomain
 	Suffix     string   // suffix is everything except `Sub` (Note: if domain is not multilevel Suffix==Root)
 	MultiLevel []string // (Optional) store prefix of multi level subdomains
+	// Template is set when wildcards are present in middle of domain (ex: prod.*.example.com)
+	// each wildcard is a slot filled with payload values (see Options.WildcardPayload)
+	Template string
+	// Vars contains extra variables of input (ex: fields of structured inputs)
+	// they never override variables derived from domain
+	Vars map[string]string
+	// tokenizer splits Sub into tokens (If nil, DefaultTokenizer is used)
+	tokenizer *Tokenizer
+	// prefix contains labels (with trailing dot) preceding Sub when
+	// input is viewed at one of its deeper levels (ex: `api.` for v1 of api.v1.example.com)
+	prefix string
 }
 
 // GetMap returns variables map of input
@@ -34,6 +112,39 @@ func (i *Input) GetMap() map[string]interface{} {
 	for k, v := range i.MultiLevel {
 		m["sub"+strconv.Itoa(k+1)] = v
 	}
+	if i.Sub != "" {
+		labels := append([]string{i.Sub}, i.MultiLevel...)
+		// last label before root (ex: c for a.b.c.example.com)
+		m["sub_last"] = labels[len(labels)-1]
+		// all labels before root (ex: a.b.c for a.b.c.example.com)
+		m["subs"] = strings.Join(labels, ".")
+		// suffix after dropping N more labels (ex: suffix1 is c.example.com for a.b.c.example.com)
+		for n := 1; n <= len(i.MultiLevel); n++ {
+			m["suffix"+strconv.Itoa(n)] = strings.Join(append(labels[n+1:len(labels):len(labels)], i.Root), ".")
+		}
+	}
+	// no of labels before root
+	m["depth"] = strconv.Itoa(i.Depth())
+	if i.Sub != "" {
+		// tokens of sub (ex: subtok1=api, subtok2=gateway, subtok_sep=- for api-gateway)
+		tokenizer := i.tokenizer
+		if tokenizer == nil {
+			tokenizer = DefaultTokenizer
+		}
+		tokens, separator := tokenizer.Tokenize(i.Sub)
+		for k, v := range tokens {
+			m["subtok"+strconv.Itoa(k+1)] = v
+		}
+		if len(tokens) > 0 {
+			m["subtok_last"] = tokens[len(tokens)-1]
+		}
+		m["subtok_sep"] = separator
+	}
+	for k, v := range i.Vars {
+		if !reservedVarRegex.MatchString(k) {
+			m[k] = v
+		}
+	}
 	for k, v := range m {
 		if v == "" {
 			// purge empty vars
@@ -43,9 +154,57 @@ func (i *Input) GetMap() map[string]interface{} {
 	return m
 }
 
+// Hostname returns full hostname of input (ex: api.v1.example.com)
+func (i *Input) Hostname() string {
+	if i.Sub == "" {
+		return i.prefix + i.Root
+	}
+	return i.prefix + strings.Join(append(append([]string{i.Sub}, i.MultiLevel...), i.Root), ".")
+}
+
+// Depth returns number of subdomain labels before root (ex: 2 for api.v1.example.com)
+func (i *Input) Depth() int {
+	if i.Sub == "" {
+		return 0
+	}
+	return len(i.MultiLevel) + 1
+}
+
+// levels returns input viewed at each label of MultiLevel where that label is Sub
+// and all preceding labels are kept as prefix
+// ex: api.v1.internal.example.com => (api.)v1.internal.example.com, (api.v1.)internal.example.com
+func (i *Input) levels() []*Input {
+	labels := append([]string{i.Sub}, i.MultiLevel...)
+	var levels []*Input
+	for n := 1; n < len(labels); n++ {
+		level := &Input{
+			TLD:       i.TLD,
+			ETLD:      i.ETLD,
+			SLD:       i.SLD,
+			Root:      i.Root,
+			Sub:       labels[n],
+			Suffix:    strings.Join(append(labels[n+1:len(labels):len(labels)], i.Root), "."),
+			Vars:      i.Vars,
+			prefix:    i.prefix + strings.Join(labels[:n], ".") + ".",
+			tokenizer: i.tokenizer,
+		}
+		if n+1 < len(labels) {
+			level.MultiLevel = labels[n+1:]
+		}
+		levels = append(levels, level)
+	}
+	return levels
+}
+
 // NewInput parses a URL or domain string into structured Input variables.
 // It extracts TLD, eTLD, SLD, root domain, subdomains, and multi-level components.
 func NewInput(inputURL string) (*Input, error) {
+	return NewInputWithSuffixList(inputURL, nil)
+}
+
+// NewInputWithSuffixList parses a URL or domain string like NewInput while consulting
+// given suffix list (if any) before embedded public suffix list
+func NewInputWithSuffixList(inputURL string, suffixes *SuffixList) (*Input, error) {
 	URL, err := urlutil.Parse(inputURL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse URL: %w", err)
@@ -57,25 +216,30 @@ func NewInput(inputURL string) (*Input, error) {
 	}
 
 	// Handle wildcard domains
-	if strings.Contains(hostname, "*") {
-		if strings.HasPrefix(hostname, "*.") {
+	if strings.Contains(hostname, WildcardSlot) {
+		if strings.HasPrefix(hostname, WildcardSlot+".") {
 			// Remove leading wildcard (e.g., *.example.com -> example.com)
-			hostname = strings.TrimPrefix(hostname, "*.")
+			hostname = strings.TrimPrefix(hostname, WildcardSlot+".")
 			URL.Host = strings.Replace(URL.Host, URL.Hostname(), hostname, 1)
+			if strings.HasPrefix(hostname, WildcardSlot+".") {
+				return nil, fmt.Errorf("multiple leading wildcards not supported: %s", inputURL)
+			}
 		}
-		// If * is present in middle (e.g., prod.*.hackerone.com), reject it
-		if strings.Contains(hostname, "*") {
-			return nil, fmt.Errorf("wildcard in middle of domain not supported: %s", inputURL)
+	}
+
+	// internationalized names are parsed in punycode form and stored in unicode form
+	// so that inputs in either form are normalized consistently
+	idn := isIDN(hostname)
+	if idn {
+		if hostname, err = toASCIIName(hostname); err != nil {
+			return nil, err
 		}
 	}
 
 	ivar := &Input{}
 
 	// Extract public suffix (TLD or eTLD like .com or .co.uk)
-	suffix, err := publicsuffix.PublicSuffix(hostname)
-	if err != nil {
-		return nil, fmt.Errorf("failed to extract public suffix: %w", err)
-	}
+	suffix := getPublicSuffix(hostname, suffixes)
 
 	if strings.Contains(suffix, ".") {
 		// Multi-part TLD like co.uk
@@ -88,7 +252,7 @@ func NewInput(inputURL string) (*Input, error) {
 	}
 
 	// Extract root domain (eTLD+1)
-	rootDomain, err := publicsuffix.EffectiveTLDPlusOne(hostname)
+	rootDomain, err := effectiveTLDPlusOne(hostname, suffix)
 	if err != nil {
 		// This happens if input is just a TLD (e.g., ".com" or "co.uk")
 		return nil, fmt.Errorf("domain '%s' appears to be a public suffix without a registered domain", hostname)
@@ -123,5 +287,54 @@ func NewInput(inputURL string) (*Input, error) {
 		ivar.Suffix = hostname
 	}
 
+	// If * is present in middle (e.g., prod.*.hackerone.com), input is a template
+	// whose wildcards are filled with payload values instead of being mutated
+	if strings.Contains(hostname, WildcardSlot) {
+		if strings.Contains(rootDomain, WildcardSlot) {
+			return nil, fmt.Errorf("wildcard in root domain not supported: %s", inputURL)
+		}
+		ivar.Template = hostname
+	}
+
+	if idn {
+		ivar.toUnicode()
+	}
 	return ivar, nil
 }
+
+// getPublicSuffix returns public suffix of hostname
+// suffixes of custom suffix list take precedence over embedded public suffix list
+func getPublicSuffix(hostname string, suffixes *SuffixList) string {
+	if suffixes != nil {
+		if suffix, ok := suffixes.PublicSuffix(hostname); ok {
+			return suffix
+		}
+	}
+	suffix, icann := publicsuffix.PublicSuffix(hostname)
+	if !icann {
+		gologger.Debug().Msgf("public suffix '%s' of %s is not managed by ICANN", suffix, hostname)
+	}
+	return suffix
+}
+
+// effectiveTLDPlusOne returns root domain (eTLD+1) of hostname with given public suffix
+func effectiveTLDPlusOne(hostname string, suffix string) (string, error) {
+	if strings.HasPrefix(hostname, ".") || strings.HasSuffix(hostname, ".") || strings.Contains(hostname, "..") {
+		return "", fmt.Errorf("empty label in domain %q", hostname)
+	}
+	if len(hostname) <= len(suffix) || !strings.HasSuffix(hostname, "."+suffix) {
+		return "", fmt.Errorf("cannot derive eTLD+1 for domain %q", hostname)
+	}
+	prefix := strings.TrimSuffix(hostname, "."+suffix)
+	return prefix[strings.LastIndex(prefix, ".")+1:] + "." + suffix, nil
+}
+
+// toUnicode converts all punycode labels of input to unicode form
+func (i *Input) toUnicode() {
+	for _, v := range []*string{&i.TLD, &i.ETLD, &i.SLD, &i.Root, &i.Sub, &i.Suffix, &i.Template} {
+		*v = toUnicodeName(*v)
+	}
+	for k, v := range i.MultiLevel {
+		i.MultiLevel[k] = toUnicodeName(v)
+	}
+}
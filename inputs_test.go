@@ -81,7 +81,7 @@ func TestInputErrors(t *testing.T) {
 		{"invalid url", "ht!tp://invalid"},
 		{"just tld", ".com"},
 		{"just public suffix", "co.uk"},
-		{"wildcard in middle", "api.*.example.com"},
+		{"wildcard in root", "api.*.com"},
 		{"multiple wildcards", "*.*.example.com"},
 		{"empty string", ""},
 	}
@@ -109,6 +109,24 @@ func TestInputWildcard(t *testing.T) {
 		require.Equal(t, "example.com", input.Root)
 		require.Equal(t, "api", input.Sub)
 	})
+
+	t.Run("wildcard in middle", func(t *testing.T) {
+		input, err := NewInput("prod.*.hackerone.com")
+		require.NoError(t, err)
+		require.Equal(t, "hackerone.com", input.Root)
+		require.Equal(t, "prod", input.Sub)
+		require.Equal(t, "prod.*.hackerone.com", input.Template)
+
+		input, err = NewInput("*.api-*.example.com")
+		require.NoError(t, err)
+		require.Equal(t, "api-*.example.com", input.Template)
+	})
+
+	t.Run("without wildcard in middle", func(t *testing.T) {
+		input, err := NewInput("*.api.example.com")
+		require.NoError(t, err)
+		require.Empty(t, input.Template)
+	})
 }
 
 func TestInputEdgeCases(t *testing.T) {
@@ -217,6 +235,186 @@ func TestInputGetMap(t *testing.T) {
 	})
 }
 
+func TestInputGetMapAggregates(t *testing.T) {
+	t.Run("deep subdomain with eTLD", func(t *testing.T) {
+		input, err := NewInput("a.b.c.d.example.co.uk")
+		require.NoError(t, err)
+
+		m := input.GetMap()
+		require.Equal(t, "d", m["sub_last"])
+		require.Equal(t, "a.b.c.d", m["subs"])
+		require.Equal(t, "4", m["depth"])
+		require.Equal(t, "b.c.d.example.co.uk", m["suffix"])
+		require.Equal(t, "c.d.example.co.uk", m["suffix1"])
+		require.Equal(t, "d.example.co.uk", m["suffix2"])
+		require.Equal(t, "example.co.uk", m["suffix3"])
+		require.NotContains(t, m, "suffix4")
+	})
+
+	t.Run("single level subdomain", func(t *testing.T) {
+		input, err := NewInput("api.example.com")
+		require.NoError(t, err)
+
+		m := input.GetMap()
+		require.Equal(t, "api", m["sub_last"])
+		require.Equal(t, "api", m["subs"])
+		require.Equal(t, "1", m["depth"])
+		require.NotContains(t, m, "suffix1")
+	})
+
+	t.Run("root domain only", func(t *testing.T) {
+		input, err := NewInput("example.com")
+		require.NoError(t, err)
+
+		m := input.GetMap()
+		require.NotContains(t, m, "sub_last")
+		require.NotContains(t, m, "subs")
+		require.Equal(t, "0", m["depth"])
+	})
+}
+
+func TestInputLevels(t *testing.T) {
+	input, err := NewInput("api.v1.internal.example.com")
+	require.NoError(t, err)
+
+	levels := input.levels()
+	require.Len(t, levels, 2)
+
+	require.Equal(t, "v1", levels[0].Sub)
+	require.Equal(t, []string{"internal"}, levels[0].MultiLevel)
+	require.Equal(t, "internal.example.com", levels[0].Suffix)
+	require.Equal(t, "api.", levels[0].prefix)
+
+	require.Equal(t, "internal", levels[1].Sub)
+	require.Nil(t, levels[1].MultiLevel)
+	require.Equal(t, "example.com", levels[1].Suffix)
+	require.Equal(t, "api.v1.", levels[1].prefix)
+	require.Equal(t, "example.com", levels[1].Root)
+
+	single, err := NewInput("api.example.com")
+	require.NoError(t, err)
+	require.Empty(t, single.levels())
+}
+
+func TestInputIDN(t *testing.T) {
+	for _, v := range []string{"api.bücher.example.de", "api.xn--bcher-kva.example.de", "https://api.bücher.example.de/path"} {
+		input, err := NewInput(v)
+		require.NoError(t, err, v)
+		require.Equal(t, "api", input.Sub, v)
+		require.Equal(t, []string{"bücher"}, input.MultiLevel, v)
+		require.Equal(t, "example.de", input.Root, v)
+		require.Equal(t, "bücher.example.de", input.Suffix, v)
+	}
+
+	t.Run("idn root", func(t *testing.T) {
+		input, err := NewInput("shop.xn--mnchen-3ya.de")
+		require.NoError(t, err)
+		require.Equal(t, "shop", input.Sub)
+		require.Equal(t, "münchen.de", input.Root)
+		require.Equal(t, "münchen", input.SLD)
+	})
+}
+
+func TestInputWithSuffixList(t *testing.T) {
+	suffixes := NewSuffixList()
+	require.NoError(t, suffixes.AddSuffix("local"))
+	require.NoError(t, suffixes.AddRoot("svc.cluster.local"))
+	require.NoError(t, suffixes.AddRoot("platform.example.com"))
+
+	testcases := []struct {
+		domain string
+		root   string
+		sub    string
+		suffix string
+		etld   string
+	}{
+		{domain: "api.v1.corp.local", root: "corp.local", sub: "api", suffix: "v1.corp.local"},
+		{domain: "api.svc.cluster.local", root: "svc.cluster.local", sub: "api", suffix: "svc.cluster.local", etld: "cluster.local"},
+		{domain: "app.platform.example.com", root: "platform.example.com", sub: "app", suffix: "platform.example.com", etld: "example.com"},
+		// domains not matched by list use embedded public suffix list
+		{domain: "api.example.com", root: "example.com", sub: "api", suffix: "example.com"},
+	}
+	for _, tc := range testcases {
+		input, err := NewInputWithSuffixList(tc.domain, suffixes)
+		require.NoError(t, err, tc.domain)
+		require.Equal(t, tc.root, input.Root, tc.domain)
+		require.Equal(t, tc.sub, input.Sub, tc.domain)
+		require.Equal(t, tc.suffix, input.Suffix, tc.domain)
+		require.Equal(t, tc.etld, input.ETLD, tc.domain)
+	}
+
+	t.Run("public suffix", func(t *testing.T) {
+		_, err := NewInputWithSuffixList("local", suffixes)
+		require.Error(t, err)
+	})
+
+	t.Run("without suffix list", func(t *testing.T) {
+		input, err := NewInput("api.svc.cluster.local")
+		require.NoError(t, err)
+		require.Equal(t, "cluster.local", input.Root)
+	})
+}
+
+func TestInputGetMapVars(t *testing.T) {
+	input, err := NewInput("api.example.com")
+	require.NoError(t, err)
+	input.Vars = map[string]string{"source": "crtsh", "sub": "www", "sub1": "v1", "env": ""}
+
+	m := input.GetMap()
+	require.Equal(t, "crtsh", m["source"])
+	// variables derived from domain are never overridden
+	require.Equal(t, "api", m["sub"])
+	require.NotContains(t, m, "sub1")
+	require.NotContains(t, m, "env")
+}
+
+func TestTokenizer(t *testing.T) {
+	testcases := []struct {
+		label     string
+		tokenizer *Tokenizer
+		tokens    []string
+		separator string
+	}{
+		{label: "api-gateway-eu2", tokenizer: DefaultTokenizer, tokens: []string{"api", "gateway", "eu", "2"}, separator: "-"},
+		{label: "auth_svc-v10", tokenizer: DefaultTokenizer, tokens: []string{"auth", "svc", "v", "10"}, separator: "_"},
+		{label: "api", tokenizer: DefaultTokenizer, tokens: []string{"api"}},
+		{label: "api--v1-", tokenizer: &Tokenizer{KeepDigits: true}, tokens: []string{"api", "v1"}, separator: "-"},
+		{label: "web01_prod", tokenizer: &Tokenizer{Separators: "-"}, tokens: []string{"web", "01", "_prod"}},
+	}
+	for _, tc := range testcases {
+		tokens, separator := tc.tokenizer.Tokenize(tc.label)
+		require.Equal(t, tc.tokens, tokens, tc.label)
+		require.Equal(t, tc.separator, separator, tc.label)
+	}
+}
+
+func TestInputGetMapTokens(t *testing.T) {
+	input, err := NewInput("api-gateway-eu2.v1.example.com")
+	require.NoError(t, err)
+	m := input.GetMap()
+	require.Equal(t, "api", m["subtok1"])
+	require.Equal(t, "gateway", m["subtok2"])
+	require.Equal(t, "eu", m["subtok3"])
+	require.Equal(t, "2", m["subtok4"])
+	require.Equal(t, "2", m["subtok_last"])
+	require.Equal(t, "-", m["subtok_sep"])
+	require.NotContains(t, m, "subtok5")
+
+	t.Run("without separator", func(t *testing.T) {
+		input, err := NewInput("api.example.com")
+		require.NoError(t, err)
+		m := input.GetMap()
+		require.Equal(t, "api", m["subtok1"])
+		require.NotContains(t, m, "subtok_sep")
+	})
+
+	t.Run("root", func(t *testing.T) {
+		input, err := NewInput("example.com")
+		require.NoError(t, err)
+		require.NotContains(t, input.GetMap(), "subtok1")
+	})
+}
+
 func TestInputDifferentTLDs(t *testing.T) {
 	testcases := []struct {
 		domain       string
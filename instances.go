@@ -0,0 +1,151 @@
+package alterx
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// InstanceSeparator separates payload name and instance number of repeated variables
+// instances draw values independently from same payload (ex: {{word#1}}.{{word#2}})
+const InstanceSeparator = "#"
+
+// Instance constraint directives (ex: distinct,ordered:{{word#1}}.{{word#2}}.{{suffix}})
+const (
+	// DistinctDirective requires instances of same payload to have different values (no dev.dev)
+	DistinctDirective = "distinct"
+	// OrderedDirective requires instances of same payload to follow payload order (a.b but not b.a)
+	OrderedDirective = "ordered"
+)
+
+// splitInstance returns payload name and instance number of variable (ex: word#1 => word, 1)
+func splitInstance(name string) (string, int) {
+	base, instance, ok := strings.Cut(name, InstanceSeparator)
+	if !ok {
+		return name, 0
+	}
+	number, _ := strconv.Atoi(instance)
+	return base, number
+}
+
+// getInstances groups instance variables by their payload name
+// variables of each group are sorted by their instance number
+func getInstances(vars []string) map[string][]string {
+	instances := map[string][]string{}
+	for _, v := range vars {
+		if base, number := splitInstance(v); number > 0 {
+			instances[base] = append(instances[base], v)
+		}
+	}
+	for _, v := range instances {
+		sort.Slice(v, func(i, j int) bool {
+			_, a := splitInstance(v[i])
+			_, b := splitInstance(v[j])
+			return a < b
+		})
+	}
+	return instances
+}
+
+// countInstances returns number of combinations of k instances of payload with n values
+func countInstances(n, k int, distinct, ordered bool) int {
+	switch {
+	case distinct && ordered:
+		// combinations: n!/(k!(n-k)!)
+		return binomial(n, k)
+	case distinct:
+		// variations: n!/(n-k)!
+		if k > n {
+			return 0
+		}
+		counter := 1
+		for i := 0; i < k; i++ {
+			counter *= n - i
+		}
+		return counter
+	case ordered:
+		// combinations with repetition: (n+k-1)!/(k!(n-1)!)
+		if n == 0 {
+			return 0
+		}
+		return binomial(n+k-1, k)
+	default:
+		counter := 1
+		for i := 0; i < k; i++ {
+			counter *= n
+		}
+		return counter
+	}
+}
+
+// binomial returns n choose k
+func binomial(n, k int) int {
+	if k < 0 || k > n {
+		return 0
+	}
+	counter := 1
+	for i := 1; i <= k; i++ {
+		counter = counter * (n - k + i) / i
+	}
+	return counter
+}
+
+// instanceFilter returns a function that reports if values of instances satisfy
+// constraints of pattern. nil is returned if pattern has no constraints
+func (p *pattern) instanceFilter(instances map[string][]string, payloads map[string]Generator) func(varMap map[string]interface{}) bool {
+	if (!p.distinct && !p.ordered) || len(instances) == 0 {
+		return nil
+	}
+	positions := map[string]func(value string) int{}
+	if p.ordered {
+		for _, vars := range instances {
+			for _, v := range vars {
+				positions[v] = positionFunc(payloads[v])
+			}
+		}
+	}
+	return func(varMap map[string]interface{}) bool {
+		for _, vars := range instances {
+			for i := 1; i < len(vars); i++ {
+				current, _ := varMap[vars[i]].(string)
+				if p.distinct {
+					for _, prev := range vars[:i] {
+						if value, _ := varMap[prev].(string); value == current {
+							return false
+						}
+					}
+				}
+				if p.ordered {
+					prev, _ := varMap[vars[i-1]].(string)
+					if positions[vars[i]](current) < positions[vars[i-1]](prev) {
+						return false
+					}
+				}
+			}
+		}
+		return true
+	}
+}
+
+// positionFunc returns a function that returns position of value in generator
+func positionFunc(generator Generator) func(value string) int {
+	if r, ok := generator.(*Range); ok {
+		// positions of range values are calculated instead of materializing range
+		return func(value string) int {
+			number, err := strconv.ParseInt(value, r.base(), 64)
+			if err != nil {
+				return -1
+			}
+			return (int(number) - r.Start) / r.step()
+		}
+	}
+	positions := make(map[string]int, generator.Len())
+	for i := 0; i < generator.Len(); i++ {
+		if _, ok := positions[generator.At(i)]; !ok {
+			positions[generator.At(i)] = i
+		}
+	}
+	return func(value string) int {
+		return positions[value]
+	}
+}
@@ -0,0 +1,44 @@
+package alterx
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitInstance(t *testing.T) {
+	name, number := splitInstance("word#2")
+	require.Equal(t, "word", name)
+	require.Equal(t, 2, number)
+
+	name, number = splitInstance("word")
+	require.Equal(t, "word", name)
+	require.Equal(t, 0, number)
+}
+
+func TestGetInstances(t *testing.T) {
+	got := getInstances([]string{"word#2", "sub", "number#1", "word#1", "word#10"})
+	require.Equal(t, map[string][]string{
+		"word":   {"word#1", "word#2", "word#10"},
+		"number": {"number#1"},
+	}, got)
+}
+
+func TestCountInstances(t *testing.T) {
+	testcases := []struct {
+		n, k              int
+		distinct, ordered bool
+		expected          int
+	}{
+		{n: 4, k: 2, expected: 16},
+		{n: 4, k: 2, distinct: true, expected: 12},
+		{n: 4, k: 2, ordered: true, expected: 10},
+		{n: 4, k: 2, distinct: true, ordered: true, expected: 6},
+		{n: 2, k: 3, distinct: true, expected: 0},
+		{n: 2, k: 3, distinct: true, ordered: true, expected: 0},
+		{n: 0, k: 2, ordered: true, expected: 0},
+	}
+	for _, tc := range testcases {
+		require.Equal(t, tc.expected, countInstances(tc.n, tc.k, tc.distinct, tc.ordered), "%+v", tc)
+	}
+}
@@ -27,6 +27,10 @@ func init() {
 		if bin, err := os.ReadFile(defaultPermutationCfg); err == nil {
 			var cfg alterx.Config
 			if errx := yaml.Unmarshal(bin, &cfg); errx == nil {
+				if len(cfg.Classes) == 0 {
+					// configs saved by older versions have no token classes
+					cfg.Classes = alterx.DefaultConfig.Classes
+				}
 				alterx.DefaultConfig = cfg
 				return
 			}
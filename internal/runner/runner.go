@@ -1,12 +1,15 @@
 package runner
 
 import (
+	"bufio"
+	"bytes"
 	"fmt"
 	"io"
 	"math"
 	"os"
 	"strings"
 
+	"github.com/projectdiscovery/alterx"
 	"github.com/projectdiscovery/goflags"
 	"github.com/projectdiscovery/gologger"
 	"github.com/projectdiscovery/gologger/levels"
@@ -14,6 +17,9 @@ import (
 	updateutils "github.com/projectdiscovery/utils/update"
 )
 
+// maxInputLineSize is maximum size of a single input line (ex: a jsonl record)
+const maxInputLineSize = 1024 * 1024
+
 type Options struct {
 	Domains            goflags.StringSlice // Subdomains to use as base
 	Patterns           goflags.StringSlice // Input Patterns
@@ -22,12 +28,33 @@ type Options struct {
 	Config             string
 	PermutationConfig  string
 	Estimate           bool
+	Learn              bool
 	DisableUpdateCheck bool
 	Verbose            bool
 	Silent             bool
 	Enrich             bool
+	EnrichKey          string
+	EnrichMinCount     int
+	EnrichMinLength    int
+	EnrichMaxWords     int
+	EnrichFrom         goflags.StringSlice
+	EnrichCorpusWords  bool
+	PerLevel           bool
+	LevelMutations     goflags.StringSlice
+	WildcardPayload    string
+	TokenSwap          bool
+	SeriesWindow       int
+	Mode               string
+	Validation         string
+	IDNOutput          string
+	SuffixList         string
+	Roots              goflags.StringSlice
+	InputFormat        string
+	InputColumn        string
 	Limit              int
 	MaxSize            int
+	// DomainChan streams domains read from stdin
+	DomainChan <-chan string
 	// internal/unexported fields
 	wordlists goflags.RuntimeMap
 }
@@ -39,13 +66,16 @@ func ParseFlags() *Options {
 	flagSet.SetDescription(`Fast and customizable subdomain wordlist generator using DSL.`)
 
 	flagSet.CreateGroup("input", "Input",
-		flagSet.StringSliceVarP(&opts.Domains, "list", "l", nil, "subdomains to use when creating permutations (stdin, comma-separated, file)", goflags.FileCommaSeparatedStringSliceOptions),
+		flagSet.StringSliceVarP(&opts.Domains, "list", "l", nil, "subdomains to use when creating permutations (stdin, comma-separated, file)", goflags.StringSliceOptions),
+		flagSet.StringVarP(&opts.InputFormat, "input-format", "if", alterx.InputFormatPlain, "format of input subdomains (plain,jsonl,csv)"),
+		flagSet.StringVarP(&opts.InputColumn, "input-column", "ic", "", "json field or csv column (name or 1-based index) containing subdomain"),
 		flagSet.StringSliceVarP(&opts.Patterns, "pattern", "p", nil, "custom permutation patterns input to generate (comma-seperated, file)", goflags.FileCommaSeparatedStringSliceOptions),
 		flagSet.RuntimeMapVarP(&opts.wordlists, "payload", "pp", nil, "custom payload pattern input to replace/use in key=value format (-pp 'word=words.txt')"),
 	)
 
 	flagSet.CreateGroup("output", "Output",
 		flagSet.BoolVarP(&opts.Estimate, "estimate", "es", false, "estimate permutation count without generating payloads"),
+		flagSet.BoolVarP(&opts.Learn, "learn", "ln", false, "learn patterns and payloads from input subdomains and write them as permutation config"),
 		flagSet.StringVarP(&opts.Output, "output", "o", "", "output file to write altered subdomain list"),
 		flagSet.SizeVarP(&maxFileSize, "max-size", "ms", "", "Max export data size (kb, mb, gb, tb) (default mb)"),
 		flagSet.BoolVarP(&opts.Verbose, "verbose", "v", false, "display verbose output"),
@@ -56,6 +86,22 @@ func ParseFlags() *Options {
 	flagSet.CreateGroup("config", "Config",
 		flagSet.StringVar(&opts.Config, "config", "", `alterx cli config file (default '$HOME/.config/alterx/config.yaml')`),
 		flagSet.BoolVarP(&opts.Enrich, "enrich", "en", false, "enrich wordlist by extracting words from input"),
+		flagSet.StringSliceVarP(&opts.EnrichFrom, "enrich-from", "ef", nil, "local files or directories (js, html, config, certificates) to extract subdomains from (implies -enrich)", goflags.CommaSeparatedStringSliceOptions),
+		flagSet.BoolVarP(&opts.EnrichCorpusWords, "enrich-corpus-words", "ecw", false, "also extract words from whole text of -enrich-from files (filtered by stoplist, min 3 occurrences)"),
+		flagSet.StringVarP(&opts.EnrichKey, "enrich-key", "ek", "", "payload to add enriched words to (ex: enriched => {{enriched}}) (default word)"),
+		flagSet.IntVarP(&opts.EnrichMinCount, "enrich-min-count", "emc", 0, "minimum number of inputs an enriched word must appear in (default 1)"),
+		flagSet.IntVarP(&opts.EnrichMinLength, "enrich-min-length", "eml", 0, "minimum length of enriched words (default 3)"),
+		flagSet.IntVarP(&opts.EnrichMaxWords, "enrich-max-words", "emw", 0, "maximum number of most frequent enriched words (default 0 = no limit)"),
+		flagSet.BoolVarP(&opts.PerLevel, "per-level", "pl", false, "apply patterns to every label of multi-level subdomains"),
+		flagSet.StringVarP(&opts.Mode, "mode", "m", "", "attack mode used to combine payloads (clusterbomb,pitchfork,sniper)"),
+		flagSet.StringVarP(&opts.Validation, "validation", "val", "strict", "dns name validation of permutations (strict,lenient,off)"),
+		flagSet.StringVar(&opts.IDNOutput, "idn", "ascii", "output form of internationalized domain names (ascii,unicode)"),
+		flagSet.StringVarP(&opts.SuffixList, "suffix-list", "sl", "", "additional public suffix list file (psl format) consulted before embedded list"),
+		flagSet.StringSliceVarP(&opts.Roots, "roots", "rt", nil, "domains to treat as root domains (comma-separated, file)", goflags.FileCommaSeparatedStringSliceOptions),
+		flagSet.StringSliceVarP(&opts.LevelMutations, "level-mutation", "lm", nil, "level mutations to apply (insert,delete,duplicate,promote)", goflags.NormalizedStringSliceOptions),
+		flagSet.IntVarP(&opts.SeriesWindow, "series-window", "sw", 0, "extrapolate numeric series of inputs to numbers within window (ex: web01 => web02...web06 for 5, default 5 with -enrich)"),
+		flagSet.BoolVarP(&opts.TokenSwap, "token-swap", "ts", false, "swap tokens of labels with other members of their class (ex: dev => prod)"),
+		flagSet.StringVarP(&opts.WildcardPayload, "wildcard-payload", "wp", "", "payload used to fill wildcards of template inputs like prod.*.example.com (default word)"),
 		flagSet.StringVar(&opts.PermutationConfig, "ac", "", fmt.Sprintf(`alterx permutation config file (default '$HOME/.config/alterx/permutation_%v.yaml')`, version)),
 		flagSet.IntVar(&opts.Limit, "limit", 0, "limit the number of results to return (default 0)"),
 	)
@@ -117,23 +163,84 @@ func ParseFlags() *Options {
 		}
 	}
 
+	// inputs are read line by line since lines may contain annotations or structured records
+	opts.Domains = expandInputs(opts.Domains, opts.InputFormat)
+
 	// read from stdin
 	if fileutil.HasStdin() {
-		bin, err := io.ReadAll(os.Stdin)
-		if err != nil {
-			gologger.Error().Msgf("failed to read input from stdin got %v", err)
+		if opts.Estimate || opts.Enrich || len(opts.EnrichFrom) > 0 || opts.SeriesWindow > 0 || opts.Learn {
+			// estimate, enrich, series and learn require all inputs before generating permutations
+			bin, err := io.ReadAll(os.Stdin)
+			if err != nil {
+				gologger.Error().Msgf("failed to read input from stdin got %v", err)
+			}
+			opts.Domains = append(opts.Domains, splitInputs(bin)...)
+		} else {
+			opts.DomainChan = readInputs(os.Stdin)
 		}
-		opts.Domains = strings.Fields(string(bin))
 	}
 
-	// TODO: replace Options.Domains with Input String Channel
-	if len(opts.Domains) == 0 {
+	if len(opts.Domains) == 0 && opts.DomainChan == nil {
 		gologger.Fatal().Msgf("alterx: no input found")
 	}
 
 	return opts
 }
 
+// expandInputs reads input lines from files and splits comma separated plain inputs
+// structured inputs (ex: jsonl) are kept as is since they may contain commas
+func expandInputs(values []string, format string) []string {
+	var inputs []string
+	for _, v := range values {
+		if fileutil.FileExists(v) {
+			bin, err := os.ReadFile(v)
+			if err != nil {
+				gologger.Error().Msgf("failed to read input file %v got %v", v, err)
+				continue
+			}
+			inputs = append(inputs, splitInputs(bin)...)
+			continue
+		}
+		if format == alterx.InputFormatPlain {
+			inputs = append(inputs, strings.Split(v, ",")...)
+		} else {
+			inputs = append(inputs, v)
+		}
+	}
+	return inputs
+}
+
+// splitInputs splits data into non-empty input lines
+func splitInputs(data []byte) []string {
+	var inputs []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(nil, maxInputLineSize)
+	for scanner.Scan() {
+		if v := strings.TrimSpace(scanner.Text()); v != "" {
+			inputs = append(inputs, v)
+		}
+	}
+	return inputs
+}
+
+// readInputs reads input lines from reader into a channel
+// channel is closed once reader is exhausted
+func readInputs(r io.Reader) <-chan string {
+	inputs := make(chan string, 100)
+	go func() {
+		defer close(inputs)
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(nil, maxInputLineSize)
+		for scanner.Scan() {
+			inputs <- scanner.Text()
+		}
+		if err := scanner.Err(); err != nil {
+			gologger.Error().Msgf("failed to read input from stdin got %v", err)
+		}
+	}()
+	return inputs
+}
+
 func printVersion() {
 	gologger.Info().Msgf("Current version: %s", version)
 	os.Exit(0)
@@ -0,0 +1,374 @@
+package alterx
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// DefaultLearnMinSupport is minimum number of observed names a learned pattern must explain
+	DefaultLearnMinSupport = 2
+	// learnWordPayload and learnNumberPayload are base names of learned payloads and ranges
+	learnWordPayload   = "word"
+	learnNumberPayload = "number"
+	// learnMergeOverlap is minimum overlap of values of two slots to share a payload
+	learnMergeOverlap = 0.5
+)
+
+// LearnOptions contains options of pattern inference
+type LearnOptions struct {
+	// MinSupport is minimum number of observed names a pattern must explain (default: 2)
+	MinSupport int
+	// MaxPatterns limits number of learned patterns (0 = no limit)
+	MaxPatterns int
+	// Tokenizer splits labels into tokens (If nil, DefaultTokenizer is used)
+	Tokenizer *Tokenizer
+	// SuffixList is consulted before embedded public suffix list while parsing names
+	SuffixList *SuffixList
+}
+
+// LearnedPattern is an inferred pattern along with number of observed names it explains
+type LearnedPattern struct {
+	Pattern string
+	Count   int
+}
+
+// Learned contains result of pattern inference
+type Learned struct {
+	// Patterns are learned patterns ranked by number of observed names they explain
+	Patterns []LearnedPattern
+	// Config is ready to use permutation config containing patterns and payloads filling them
+	Config *Config
+}
+
+// learnSegment is a token or separator of a label
+type learnSegment struct {
+	value string
+	kind  byte // 'a' for words, 'd' for numbers and 's' for separators
+}
+
+// learnSlot is a variable position of a shape along with observed values
+type learnSlot struct {
+	kind   byte
+	values map[string]struct{}
+	name   string
+}
+
+// learnShape groups labels with same sequence of token kinds and separators
+type learnShape struct {
+	key   string
+	slots []*learnSlot // slots of segments (nil for separators)
+	names int
+	parts [][]learnSegment
+}
+
+// Learn infers naming patterns of target from observed subdomains
+// leftmost label of every subdomain is split into tokens and labels with same structure
+// (ex: api-prod1, web-dev2) are generalized into a pattern (ex: {{word}}-{{word2}}{{number}}.{{suffix}})
+// tokens that never vary are kept as literals and numbers are learned as ranges
+func Learn(domains []string, opts *LearnOptions) (*Learned, error) {
+	if opts == nil {
+		opts = &LearnOptions{}
+	}
+	minSupport := opts.MinSupport
+	if minSupport <= 0 {
+		minSupport = DefaultLearnMinSupport
+	}
+	tokenizer := opts.Tokenizer
+	if tokenizer == nil {
+		tokenizer = DefaultTokenizer
+	}
+
+	shapes := map[string]*learnShape{}
+	seen := map[string]struct{}{}
+	for _, domain := range domains {
+		input, err := NewInputWithSuffixList(NormalizeDomain(domain), opts.SuffixList)
+		if err != nil || input.Sub == "" || input.Template != "" {
+			continue
+		}
+		if _, ok := seen[input.Hostname()]; ok {
+			continue
+		}
+		seen[input.Hostname()] = struct{}{}
+		segments := segmentLabel(input.Sub, tokenizer)
+		key := shapeKey(segments)
+		shape, ok := shapes[key]
+		if !ok {
+			shape = &learnShape{key: key}
+			shapes[key] = shape
+		}
+		shape.names++
+		shape.parts = append(shape.parts, segments)
+	}
+
+	var learned []*learnShape
+	for _, shape := range shapes {
+		if shape.names >= minSupport {
+			shape.collectSlots()
+			learned = append(learned, shape)
+		}
+	}
+	if len(learned) == 0 {
+		return nil, fmt.Errorf("no patterns explain at least %v of %v observed names", minSupport, len(seen))
+	}
+	sort.Slice(learned, func(i, j int) bool {
+		if learned[i].names != learned[j].names {
+			return learned[i].names > learned[j].names
+		}
+		return learned[i].key < learned[j].key
+	})
+	if opts.MaxPatterns > 0 && len(learned) > opts.MaxPatterns {
+		learned = learned[:opts.MaxPatterns]
+	}
+
+	config := &Config{Payloads: map[string][]string{}, Ranges: map[string]*Range{}}
+	nameSlots(learned, config)
+	result := &Learned{Config: config}
+	for _, shape := range learned {
+		pattern := shape.pattern()
+		result.Patterns = append(result.Patterns, LearnedPattern{Pattern: pattern, Count: shape.names})
+		config.Patterns = append(config.Patterns, pattern)
+	}
+	return result, nil
+}
+
+// YAML returns learned config in yaml format where every pattern is annotated
+// with number of observed names it explains
+func (l *Learned) YAML() ([]byte, error) {
+	var node yaml.Node
+	if err := node.Encode(l.Config); err != nil {
+		return nil, err
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value != "patterns" {
+			continue
+		}
+		for k, v := range node.Content[i+1].Content {
+			v.LineComment = fmt.Sprintf("explains %v names", l.Patterns[k].Count)
+		}
+	}
+	var buff bytes.Buffer
+	encoder := yaml.NewEncoder(&buff)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&node); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buff.Bytes(), nil
+}
+
+// segmentLabel splits label into words, numbers and separators
+func segmentLabel(label string, tokenizer *Tokenizer) []learnSegment {
+	separators := tokenizer.Separators
+	if separators == "" {
+		separators = DefaultTokenSeparators
+	}
+	var segments []learnSegment
+	add := func(value string, kind byte) {
+		if value == "" {
+			return
+		}
+		if kind == 'a' && isNumber(value) {
+			kind = 'd'
+		}
+		segments = append(segments, learnSegment{value: value, kind: kind})
+	}
+	start := 0
+	for i := 0; i < len(label); i++ {
+		if strings.IndexByte(separators, label[i]) >= 0 {
+			add(label[start:i], 'a')
+			add(label[i:i+1], 's')
+			start = i + 1
+			continue
+		}
+		if !tokenizer.KeepDigits && i > start && isDigit(label[i]) != isDigit(label[i-1]) {
+			add(label[start:i], 'a')
+			start = i
+		}
+	}
+	add(label[start:], 'a')
+	return segments
+}
+
+// shapeKey returns structure of segments (ex: a-ad for api-prod1)
+func shapeKey(segments []learnSegment) string {
+	var sb strings.Builder
+	for _, v := range segments {
+		if v.kind == 's' {
+			sb.WriteString(v.value)
+		} else {
+			sb.WriteByte(v.kind)
+		}
+	}
+	return sb.String()
+}
+
+// collectSlots collects observed values of every token position of shape
+func (s *learnShape) collectSlots() {
+	s.slots = make([]*learnSlot, len(s.parts[0]))
+	for i, segment := range s.parts[0] {
+		if segment.kind != 's' {
+			s.slots[i] = &learnSlot{kind: segment.kind, values: map[string]struct{}{}}
+		}
+	}
+	for _, part := range s.parts {
+		for i, segment := range part {
+			if s.slots[i] != nil {
+				s.slots[i].values[segment.value] = struct{}{}
+			}
+		}
+	}
+}
+
+// pattern returns pattern of shape using names of its slots
+func (s *learnShape) pattern() string {
+	var sb strings.Builder
+	for i, segment := range s.parts[0] {
+		if s.slots[i] == nil || s.slots[i].name == "" {
+			// separators and tokens that never vary are kept as is
+			sb.WriteString(segment.value)
+			continue
+		}
+		sb.WriteString(ParenthesisOpen + s.slots[i].name + ParenthesisClose)
+	}
+	return sb.String() + "." + ParenthesisOpen + "suffix" + ParenthesisClose
+}
+
+// nameSlots merges varying slots of shapes with overlapping values into shared payloads
+// and adds them to config. words are merged into payloads and numbers into ranges
+func nameSlots(shapes []*learnShape, config *Config) {
+	var slots []*learnSlot
+	for _, shape := range shapes {
+		for _, v := range shape.slots {
+			if v != nil && len(v.values) > 1 {
+				slots = append(slots, v)
+			}
+		}
+	}
+	// union find of slots with overlapping values
+	parent := make([]int, len(slots))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	for i := range slots {
+		for j := i + 1; j < len(slots); j++ {
+			if slots[i].kind == slots[j].kind && slotsOverlap(slots[i], slots[j]) {
+				parent[find(j)] = find(i)
+			}
+		}
+	}
+	groups := map[int][]*learnSlot{}
+	for i, v := range slots {
+		groups[find(i)] = append(groups[find(i)], v)
+	}
+
+	type payload struct {
+		kind   byte
+		slots  []*learnSlot
+		values []string
+	}
+	var payloads []*payload
+	for _, group := range groups {
+		values := map[string]struct{}{}
+		for _, v := range group {
+			for value := range v.values {
+				values[value] = struct{}{}
+			}
+		}
+		p := &payload{kind: group[0].kind, slots: group}
+		for k := range values {
+			p.values = append(p.values, k)
+		}
+		sort.Strings(p.values)
+		payloads = append(payloads, p)
+	}
+	sort.Slice(payloads, func(i, j int) bool {
+		if len(payloads[i].values) != len(payloads[j].values) {
+			return len(payloads[i].values) > len(payloads[j].values)
+		}
+		return strings.Join(payloads[i].values, ",") < strings.Join(payloads[j].values, ",")
+	})
+
+	counters := map[byte]int{}
+	for _, p := range payloads {
+		counters[p.kind]++
+		name := learnWordPayload
+		if p.kind == 'd' {
+			name = learnNumberPayload
+		}
+		if counters[p.kind] > 1 {
+			name += strconv.Itoa(counters[p.kind])
+		}
+		for _, v := range p.slots {
+			v.name = name
+		}
+		if p.kind == 'd' {
+			config.Ranges[name] = learnRange(p.values)
+		} else {
+			config.Payloads[name] = p.values
+		}
+	}
+}
+
+// slotsOverlap returns true if values of slots overlap enough to be filled by same payload
+func slotsOverlap(a, b *learnSlot) bool {
+	if len(a.values) > len(b.values) {
+		a, b = b, a
+	}
+	common := 0
+	for v := range a.values {
+		if _, ok := b.values[v]; ok {
+			common++
+		}
+	}
+	return float64(common)/float64(len(a.values)) >= learnMergeOverlap
+}
+
+// learnRange returns range covering observed numbers
+// numbers are zero padded if any observed number has leading zeros (ex: 01)
+func learnRange(values []string) *Range {
+	r := &Range{Start: -1}
+	for _, v := range values {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			continue
+		}
+		if r.Start < 0 || n < r.Start {
+			r.Start = n
+		}
+		if n > r.End {
+			r.End = n
+		}
+		if len(v) > 1 && v[0] == '0' && len(v) > r.Width {
+			r.Width = len(v)
+		}
+	}
+	if r.Start < 0 {
+		r.Start = 0
+	}
+	return r
+}
+
+// isNumber returns true if value only contains ascii digits
+func isNumber(value string) bool {
+	for i := 0; i < len(value); i++ {
+		if !isDigit(value[i]) {
+			return false
+		}
+	}
+	return value != ""
+}
@@ -0,0 +1,86 @@
+package alterx
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestLearn(t *testing.T) {
+	domains := []string{
+		"api-prod1.example.com", "web-dev2.example.com", "db-stg3.example.com",
+		"https://API-dev1.example.com/login", "api-dev1.example.com",
+		"www.example.com", "mail.example.com", "x.example.com",
+		"vpn01.eu.example.com", "vpn02.example.com",
+		"legacy.test.example.com", "example.com", "invalid..example.com",
+	}
+
+	learned, err := Learn(domains, nil)
+	require.NoError(t, err)
+	require.Equal(t, []LearnedPattern{
+		{Pattern: "{{word}}.{{suffix}}", Count: 4},
+		{Pattern: "{{word2}}-{{word3}}{{number}}.{{suffix}}", Count: 4},
+		{Pattern: "vpn{{number2}}.{{suffix}}", Count: 2},
+	}, learned.Patterns)
+	require.Equal(t, map[string][]string{
+		"word":  {"legacy", "mail", "www", "x"},
+		"word2": {"api", "db", "web"},
+		"word3": {"dev", "prod", "stg"},
+	}, learned.Config.Payloads)
+	require.Equal(t, map[string]*Range{
+		"number":  {Start: 1, End: 3},
+		"number2": {Start: 1, End: 2, Width: 2},
+	}, learned.Config.Ranges)
+
+	t.Run("learned config generates observed names", func(t *testing.T) {
+		m, err := New(&Options{
+			Domains:  []string{"api-dev1.example.com"},
+			Patterns: learned.Config.Patterns,
+			Payloads: learned.Config.Payloads,
+			Ranges:   learned.Config.Ranges,
+		})
+		require.NoError(t, err)
+		results := collectResults(m)
+		require.Contains(t, results, "web-dev2.example.com")
+		require.Contains(t, results, "vpn01.example.com")
+	})
+
+	t.Run("yaml", func(t *testing.T) {
+		bin, err := learned.YAML()
+		require.NoError(t, err)
+		require.Contains(t, string(bin), "- '{{word2}}-{{word3}}{{number}}.{{suffix}}' # explains 4 names\n")
+
+		var config Config
+		require.NoError(t, yaml.Unmarshal(bin, &config))
+		require.Equal(t, learned.Config.Patterns, config.Patterns)
+		require.Equal(t, learned.Config.Ranges, config.Ranges)
+	})
+
+	t.Run("options", func(t *testing.T) {
+		learned, err := Learn(domains, &LearnOptions{MinSupport: 3, MaxPatterns: 1})
+		require.NoError(t, err)
+		require.Len(t, learned.Patterns, 1)
+
+		_, err = Learn(domains, &LearnOptions{MinSupport: 10})
+		require.Error(t, err)
+	})
+
+	t.Run("merged slots", func(t *testing.T) {
+		learned, err := Learn([]string{"dev-api.example.com", "prod-api.example.com", "dev.example.com", "stg.example.com"}, nil)
+		require.NoError(t, err)
+		// slots with overlapping values share a payload and tokens that never vary are kept as is
+		require.Equal(t, []string{"{{word}}.{{suffix}}", "{{word}}-api.{{suffix}}"}, []string{learned.Patterns[0].Pattern, learned.Patterns[1].Pattern})
+		require.Equal(t, map[string][]string{"word": {"dev", "prod", "stg"}}, learned.Config.Payloads)
+	})
+}
+
+func TestSegmentLabel(t *testing.T) {
+	segments := segmentLabel("api_gw-eu2", DefaultTokenizer)
+	require.Equal(t, "a_a-ad", shapeKey(segments))
+	require.Equal(t, "2", segments[len(segments)-1].value)
+
+	segments = segmentLabel("web01", &Tokenizer{KeepDigits: true})
+	require.Equal(t, "a", shapeKey(segments))
+	require.Equal(t, "d", shapeKey(segmentLabel("2024", DefaultTokenizer)))
+}
@@ -0,0 +1,94 @@
+package alterx
+
+import (
+	"fmt"
+	"strings"
+
+	sliceutil "github.com/projectdiscovery/utils/slice"
+)
+
+// Level mutations change structure of subdomain labels instead of their content
+const (
+	// LevelInsert inserts a word between any two labels (ex: api.example.com => api.internal.example.com)
+	LevelInsert = "insert"
+	// LevelDelete deletes a label (ex: x.staging.example.com => x.example.com)
+	LevelDelete = "delete"
+	// LevelDuplicate duplicates a label (ex: api.v1.example.com => api.v1.v1.example.com)
+	LevelDuplicate = "duplicate"
+	// LevelPromote promotes {{sub1}} to {{sub}} (ex: api.v1.example.com => v1.api.example.com)
+	LevelPromote = "promote"
+)
+
+// levelInsertPayload is payload used to insert new labels
+const levelInsertPayload = "word"
+
+// AllLevelMutations contains all available level mutations
+var AllLevelMutations = []string{LevelInsert, LevelDelete, LevelDuplicate, LevelPromote}
+
+// validateLevelMutations checks if all given level mutations are supported
+func validateLevelMutations(mutations []string) error {
+	for _, v := range mutations {
+		if !sliceutil.Contains(AllLevelMutations, v) {
+			return fmt.Errorf("unknown level mutation '%s': supported values are %s", v, strings.Join(AllLevelMutations, ","))
+		}
+	}
+	return nil
+}
+
+// mutateLevels generates hostnames by applying enabled level mutations to labels of input
+// and invokes callback for each of them. it stops early if callback returns false
+func (m *Mutator) mutateLevels(input *Input, callback func(string) bool) bool {
+	if input.Sub == "" {
+		return true
+	}
+	labels := append([]string{input.Sub}, input.MultiLevel...)
+	// build joins given label groups along with root to create hostname
+	build := func(parts ...[]string) string {
+		var all []string
+		for _, v := range parts {
+			all = append(all, v...)
+		}
+		return strings.Join(append(all, input.Root), ".")
+	}
+	for _, mutation := range m.Options.LevelMutations {
+		switch mutation {
+		case LevelInsert:
+			// insert after every label i.e between label and next label or root
+			for i := range labels {
+				for _, word := range m.Options.Payloads[levelInsertPayload] {
+					if word == labels[i] || (i+1 < len(labels) && word == labels[i+1]) {
+						// skip api.api.example.com like duplicates
+						continue
+					}
+					if !callback(build(labels[:i+1], []string{word}, labels[i+1:])) {
+						return false
+					}
+				}
+			}
+		case LevelDelete:
+			if len(labels) < 2 {
+				// deleting only label results in root domain
+				continue
+			}
+			for i := range labels {
+				if !callback(build(labels[:i], labels[i+1:])) {
+					return false
+				}
+			}
+		case LevelDuplicate:
+			for i := range labels {
+				if !callback(build(labels[:i+1], labels[i:])) {
+					return false
+				}
+			}
+		case LevelPromote:
+			if len(labels) < 2 || labels[0] == labels[1] {
+				continue
+			}
+			if !callback(build([]string{labels[1], labels[0]}, labels[2:])) {
+				return false
+			}
+		}
+	}
+	return true
+}
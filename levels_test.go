@@ -0,0 +1,55 @@
+package alterx
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func getLevelMutations(t *testing.T, domain string, mutations ...string) []string {
+	input, err := NewInput(domain)
+	require.NoError(t, err)
+	m := &Mutator{Options: &Options{
+		Payloads:       map[string][]string{"word": {"dev", "v1"}},
+		LevelMutations: mutations,
+	}}
+	var results []string
+	m.mutateLevels(input, func(value string) bool {
+		results = append(results, value)
+		return true
+	})
+	return results
+}
+
+func TestMutateLevels(t *testing.T) {
+	t.Run("insert", func(t *testing.T) {
+		require.Equal(t, []string{
+			"api.dev.v1.example.com",
+			"api.v1.dev.example.com",
+		}, getLevelMutations(t, "api.v1.example.com", LevelInsert))
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		require.Equal(t, []string{"staging.example.com", "x.example.com"}, getLevelMutations(t, "x.staging.example.com", LevelDelete))
+		// root domain is never generated
+		require.Empty(t, getLevelMutations(t, "api.example.com", LevelDelete))
+	})
+
+	t.Run("duplicate", func(t *testing.T) {
+		require.Equal(t, []string{"api.api.v1.example.com", "api.v1.v1.example.com"}, getLevelMutations(t, "api.v1.example.com", LevelDuplicate))
+	})
+
+	t.Run("promote", func(t *testing.T) {
+		require.Equal(t, []string{"v1.api.internal.example.com"}, getLevelMutations(t, "api.v1.internal.example.com", LevelPromote))
+		require.Empty(t, getLevelMutations(t, "api.example.com", LevelPromote))
+	})
+
+	t.Run("root input", func(t *testing.T) {
+		require.Empty(t, getLevelMutations(t, "example.com", AllLevelMutations...))
+	})
+}
+
+func TestValidateLevelMutations(t *testing.T) {
+	require.NoError(t, validateLevelMutations(AllLevelMutations))
+	require.Error(t, validateLevelMutations([]string{"swap"}))
+}
@@ -1,15 +1,14 @@
 package alterx
 
 import (
-	"bytes"
 	"context"
 	"fmt"
 	"io"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
-	"github.com/projectdiscovery/fasttemplate"
 	"github.com/projectdiscovery/gologger"
 	"github.com/projectdiscovery/utils/dedupe"
 	errorutil "github.com/projectdiscovery/utils/errors"
@@ -26,20 +25,78 @@ var (
 type Options struct {
 	// Domains is the list of domains to use as base for permutations
 	Domains []string
+	// DomainChan when set, domains are also read lazily from channel after Domains and
+	// permutations are generated while it is still open. it must be closed by caller
+	DomainChan <-chan string
+	// InputFormat is format of Domains and DomainChan items (plain, jsonl or csv)
+	// extra fields of structured inputs are available as variables of that input
+	// If empty, InputFormatPlain is used
+	InputFormat string
+	// InputColumn is json field or csv column containing domain (see InputParser)
+	InputColumn string
 	// Payloads contains words to use while creating permutations
 	// If empty, DefaultWordList is used
 	Payloads map[string][]string
+	// Ranges contains numeric payload generators that are evaluated lazily
+	// If both Payloads and Ranges are empty, DefaultConfig ranges are used
+	Ranges map[string]*Range
+	// Tuples contains payloads whose entries consist of correlated fields
+	// that are used in patterns as {{name.field}} (ex: {{env.abbr}})
+	Tuples map[string]Tuples
+	// SuffixList contains additional public suffixes and root domains consulted
+	// before embedded public suffix list while parsing inputs (ex: corp.local)
+	SuffixList *SuffixList
 	// Patterns is the list of patterns to use while creating permutations
 	// If empty, DefaultPatterns are used
 	Patterns []string
 	// Limit restricts output results (0 = no limit)
 	Limit int
-	// Enrich when true, alterx extracts possible words from input
-	// and adds them to default payloads word,number
+	// Enrich when true, alterx extracts possible words from input and adds them to
+	// payload of Enrichment.Key (default: word). numeric series of inputs are
+	// extrapolated using DefaultSeriesWindow if SeriesWindow is not set
 	Enrich bool
+	// Enrichment contains thresholds and target payload of enriched words (If nil, defaults are used)
+	Enrichment *Enrichment
+	// EnrichFrom are local files or directories (ex: js, html, certificates) scanned for
+	// subdomains of input roots, which are added to Domains, and for words used by enrich.
+	// it implies Enrich
+	EnrichFrom []string
+	// PerLevel when true, patterns are also applied to every label of multi level
+	// subdomains treating that label as {{sub}} (ex: v1 of api.v1.example.com)
+	PerLevel bool
+	// LevelMutations contains level mutations to apply on labels of input
+	// (ex: insert, delete, duplicate, promote) see AllLevelMutations
+	LevelMutations []string
+	// WildcardPayload is name of payload or range used to fill wildcards of template inputs
+	// (ex: prod.*.example.com) If empty, DefaultWildcardPayload is used
+	WildcardPayload string
+	// Mode is attack mode used to combine payloads of patterns (default: clusterbomb)
+	// patterns can override it using leading directive (ex: pitchfork:{{sub}}-{{env}}.{{suffix}})
+	Mode string
+	// Validation is validation mode of generated candidates (strict, lenient or off)
+	// If empty, ValidationStrict is used
+	Validation string
+	// IDNOutput is output form of internationalized names (ascii or unicode)
+	// If empty, IDNASCII (punycode) is used
+	IDNOutput string
+	// SeriesWindow when positive, numbers in labels of inputs are extrapolated to their
+	// neighbours and gaps within window (ex: web01..web03 => web04...web08 for window 5)
+	SeriesWindow int
+	// TokenSwap when true, class members found in labels of inputs are replaced with
+	// other members of same class (ex: api-dev.example.com => api-prod.example.com)
+	TokenSwap bool
+	// Classes are semantic classes of tokens used by TokenSwap (ex: env: [dev, stg, prod])
+	// If empty, classes of default config are used
+	Classes map[string][]string
+	// Tokenizer splits sub of inputs into {{subtokN}} variables (If nil, DefaultTokenizer is used)
+	Tokenizer *Tokenizer
+	// Rules contains exclusion rules evaluated on every generated candidate before dedupe
+	Rules *Rules
 	// MaxSize limits output data size in bytes
 	MaxSize int
-	// DedupeResults when true, deduplicates all results (default: true)
+	// DisableDedupe when true, results are not deduplicated (default: false)
+	DisableDedupe bool
+	// Deprecated: results are deduplicated unless DisableDedupe is set
 	DedupeResults bool
 }
 
@@ -51,25 +108,38 @@ type Mutator struct {
 	timeTaken    time.Duration
 	// internal or unexported variables
 	maxkeyLenInBytes int
+	patterns         []*pattern               // compiled patterns
+	parser           *InputParser             // parser of input lines
+	classNames       []string                 // sorted names of token classes
+	series           map[string]*numberSeries // known numbers of numeric series
+	seenInputs       map[string]*Input        // inputs indexed by canonical hostname
+	streamedInputs   *dedupe.LevelDBBackend   // canonical hostnames of streamed inputs
+	annotations      map[string]struct{}      // names of variables annotated on inputs
+	collapsedCount   int                      // duplicate inputs dropped after normalization
+	excludedCount    int                      // candidates dropped by exclusion rules
+	rejections       map[string]int           // invalid candidates dropped per reason
+	payloadVars      map[string]interface{}   // variables available to patterns besides input variables
+	wildcardWarned   bool                     // empty wildcard payload was reported for streamed template inputs
 }
 
 // New creates and returns new mutator instance from options
 func New(opts *Options) (*Mutator, error) {
-	if len(opts.Domains) == 0 {
+	if len(opts.Domains) == 0 && opts.DomainChan == nil {
 		return nil, fmt.Errorf("no domains provided: please provide at least one domain via -l flag or stdin")
 	}
 
-	// Set default for DedupeResults if not explicitly set
-	if !opts.DedupeResults {
-		opts.DedupeResults = true // Default to true
-	}
-
 	if len(opts.Payloads) == 0 {
 		opts.Payloads = map[string][]string{}
 		if len(DefaultConfig.Payloads) == 0 {
 			return nil, fmt.Errorf("no payloads available: default payload configuration is empty and no custom payloads provided")
 		}
 		opts.Payloads = DefaultConfig.Payloads
+		if len(opts.Ranges) == 0 {
+			opts.Ranges = DefaultConfig.Ranges
+		}
+		if len(opts.Tuples) == 0 {
+			opts.Tuples = DefaultConfig.Tuples
+		}
 	}
 	if len(opts.Patterns) == 0 {
 		if len(DefaultConfig.Patterns) == 0 {
@@ -77,6 +147,9 @@ func New(opts *Options) (*Mutator, error) {
 		}
 		opts.Patterns = DefaultConfig.Patterns
 	}
+	// payloads are copied since they are modified by deduplication and enrichment
+	// which must not affect payloads of caller or default config
+	opts.Payloads = copyPayloads(opts.Payloads)
 	// purge duplicates if any
 	for k, v := range opts.Payloads {
 		dedupe := sliceutil.Dedupe(v)
@@ -85,8 +158,94 @@ func New(opts *Options) (*Mutator, error) {
 			opts.Payloads[k] = dedupe
 		}
 	}
+	for k, v := range opts.Ranges {
+		if _, ok := opts.Payloads[k]; ok {
+			return nil, fmt.Errorf("range '%s' conflicts with payload of same name", k)
+		}
+		if err := v.Validate(); err != nil {
+			return nil, fmt.Errorf("range '%s': %w", k, err)
+		}
+	}
+	for k, v := range opts.Tuples {
+		if _, ok := opts.Payloads[k]; ok {
+			return nil, fmt.Errorf("tuple '%s' conflicts with payload of same name", k)
+		}
+		if _, ok := opts.Ranges[k]; ok {
+			return nil, fmt.Errorf("tuple '%s' conflicts with range of same name", k)
+		}
+		if err := v.Validate(); err != nil {
+			return nil, fmt.Errorf("tuple '%s': %w", k, err)
+		}
+	}
+	if err := validateLevelMutations(opts.LevelMutations); err != nil {
+		return nil, err
+	}
+	if opts.TokenSwap && len(opts.Classes) == 0 {
+		opts.Classes = DefaultConfig.Classes
+	}
+	if err := validateClasses(opts.Classes); err != nil {
+		return nil, err
+	}
+	if len(opts.EnrichFrom) > 0 {
+		opts.Enrich = true
+	}
+	if opts.Enrich {
+		opts.Enrichment = opts.Enrichment.withDefaults()
+		key := opts.Enrichment.Key
+		if reservedVarRegex.MatchString(key) {
+			return nil, fmt.Errorf("enrich key '%s' conflicts with builtin variable", key)
+		}
+		if _, ok := opts.Ranges[key]; ok {
+			return nil, fmt.Errorf("enrich key '%s' conflicts with range of same name", key)
+		}
+		if _, ok := opts.Tuples[key]; ok {
+			return nil, fmt.Errorf("enrich key '%s' conflicts with tuple of same name", key)
+		}
+	}
+	if opts.WildcardPayload != "" {
+		_, isPayload := opts.Payloads[opts.WildcardPayload]
+		_, isRange := opts.Ranges[opts.WildcardPayload]
+		if !isPayload && !isRange {
+			return nil, fmt.Errorf("wildcard payload '%s' not found", opts.WildcardPayload)
+		}
+	} else {
+		opts.WildcardPayload = DefaultWildcardPayload
+	}
+	if opts.Mode != "" {
+		if err := validateAttackMode(opts.Mode); err != nil {
+			return nil, err
+		}
+	}
+	if opts.Validation == "" {
+		opts.Validation = ValidationStrict
+	}
+	if err := validateValidationMode(opts.Validation); err != nil {
+		return nil, err
+	}
+	if opts.IDNOutput == "" {
+		opts.IDNOutput = IDNASCII
+	}
+	if err := validateIDNForm(opts.IDNOutput); err != nil {
+		return nil, err
+	}
+	if opts.Rules != nil {
+		if err := opts.Rules.Compile(); err != nil {
+			return nil, fmt.Errorf("rules validation failed: %w", err)
+		}
+	}
+	parser, err := NewInputParser(opts.InputFormat, opts.InputColumn)
+	if err != nil {
+		return nil, err
+	}
 	m := &Mutator{
-		Options: opts,
+		Options:     opts,
+		parser:      parser,
+		classNames:  sortedKeys(opts.Classes),
+		series:      map[string]*numberSeries{},
+		annotations: map[string]struct{}{},
+	}
+	if opts.Enrich && opts.SeriesWindow == 0 {
+		opts.SeriesWindow = DefaultSeriesWindow
 	}
 	if err := m.validatePatterns(); err != nil {
 		return nil, fmt.Errorf("pattern validation failed: %w", err)
@@ -95,8 +254,29 @@ func New(opts *Options) (*Mutator, error) {
 		return nil, err
 	}
 	if opts.Enrich {
-		m.enrichPayloads()
+		if opts.DomainChan != nil {
+			gologger.Warning().Msgf("enrich only uses domains available before streaming input")
+		}
+		var corpus *wordCounter
+		if len(opts.EnrichFrom) > 0 {
+			if opts.Enrichment.CorpusWords {
+				corpus = newWordCounter()
+			}
+			if err := m.scanCorpora(corpus); err != nil {
+				return nil, err
+			}
+		}
+		m.enrichPayloads(corpus)
+	}
+	if !m.hasWildcardPayload() {
+		// checked after enrichment since it can add words to wildcard payload
+		for _, v := range m.Inputs {
+			if v.Template != "" {
+				return nil, fmt.Errorf("wildcard payload '%s' is empty but required by template input %s", opts.WildcardPayload, v.Template)
+			}
+		}
 	}
+	m.preparePayloadVars()
 	return m, nil
 }
 
@@ -105,11 +285,13 @@ func New(opts *Options) (*Mutator, error) {
 // the operation. Results are returned via a read-only channel.
 func (m *Mutator) Execute(ctx context.Context) <-chan string {
 	var maxBytes int
-	if m.Options.DedupeResults {
+	if !m.Options.DisableDedupe && m.Options.DomainChan == nil {
 		count := m.EstimateCount()
 		maxBytes = count * m.maxkeyLenInBytes
 	}
 
+	m.excludedCount = 0
+	m.rejections = map[string]int{}
 	results := make(chan string, len(m.Options.Patterns))
 	go func() {
 		defer close(results)
@@ -124,28 +306,19 @@ func (m *Mutator) Execute(ctx context.Context) <-chan string {
 			default:
 			}
 
-			varMap := getSampleMap(v.GetMap(), m.Options.Payloads)
-			for _, pattern := range m.Options.Patterns {
-				// Check for cancellation at the pattern level
-				select {
-				case <-ctx.Done():
-					m.timeTaken = time.Since(now)
-					return
-				default:
-				}
-
-				if err := checkMissing(pattern, varMap); err == nil {
-					statement := Replace(pattern, v.GetMap())
-					m.clusterBomb(ctx, statement, results)
-				} else {
-					gologger.Warning().Msgf("pattern '%s' has missing variables: %v, skipping", pattern, err)
-				}
-			}
+			m.executeInput(ctx, v, results)
+		}
+		if m.Options.DomainChan != nil {
+			m.executeStream(ctx, results)
 		}
 		m.timeTaken = time.Since(now)
 	}()
 
-	if m.Options.DedupeResults {
+	if !m.Options.DisableDedupe && m.Options.DomainChan != nil {
+		// results are deduplicated and emitted while they are generated
+		return dedupeStream(ctx, results)
+	}
+	if !m.Options.DisableDedupe {
 		// drain results
 		d := dedupe.NewDedupe(results, maxBytes)
 		d.Drain()
@@ -154,6 +327,63 @@ func (m *Mutator) Execute(ctx context.Context) <-chan string {
 	return results
 }
 
+// executeStream parses and generates permutations of domains read from DomainChan
+// until it is closed or context is cancelled
+func (m *Mutator) executeStream(ctx context.Context, results chan string) {
+	m.streamedInputs = dedupe.NewLevelDBBackend()
+	defer func() {
+		m.streamedInputs.Cleanup()
+		m.streamedInputs = nil
+	}()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case domain, ok := <-m.Options.DomainChan:
+			if !ok {
+				return
+			}
+			inputs, err := m.parseInputs(domain)
+			if err != nil {
+				gologger.Warning().Msgf("failed to parse input %s: %v", domain, err)
+			}
+			for _, input := range inputs {
+				if m.isDuplicateInput(input) {
+					continue
+				}
+				// series are only built from inputs available before streaming
+				// so memory does not grow with streamed inputs
+				m.executeInput(ctx, input, results)
+			}
+		}
+	}
+}
+
+// dedupeStream removes duplicates from results while they are generated
+// disk backed storage is used since number of results is not known in advance.
+// returned channel is closed only after results is closed so counters written
+// by generation can be read once it is closed
+func dedupeStream(ctx context.Context, results <-chan string) <-chan string {
+	unique := make(chan string, cap(results))
+	go func() {
+		defer close(unique)
+		backend := dedupe.NewLevelDBBackend()
+		defer backend.Cleanup()
+		for value := range results {
+			// results are drained without forwarding after cancellation
+			// until generation stops and closes them
+			if ctx.Err() != nil || !backend.Upsert(value) {
+				continue
+			}
+			select {
+			case unique <- value:
+			case <-ctx.Done():
+			}
+		}
+	}()
+	return unique
+}
+
 // ExecuteWithWriter executes Mutator and writes results directly to a type that implements io.Writer interface.
 // The context can be used to cancel the operation.
 func (m *Mutator) ExecuteWithWriter(ctx context.Context, writer io.Writer) error {
@@ -164,7 +394,11 @@ func (m *Mutator) ExecuteWithWriter(ctx context.Context, writer io.Writer) error
 		ctx = context.Background()
 	}
 
-	resChan := m.Execute(ctx)
+	// generation is cancelled once limit or max size is reached
+	// so remaining inputs are neither read nor mutated
+	execCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	resChan := m.Execute(execCtx)
 	m.payloadCount = 0
 	remainingSize := m.Options.MaxSize
 
@@ -175,16 +409,27 @@ func (m *Mutator) ExecuteWithWriter(ctx context.Context, writer io.Writer) error
 		case value, ok := <-resChan:
 			if !ok {
 				gologger.Info().Msgf("Generated %d permutations in %s", m.payloadCount, m.Time())
+				if len(m.rejections) > 0 {
+					gologger.Info().Msgf("Rejected %d invalid permutations (%s)", m.RejectedCount(), formatRejections(m.rejections))
+				}
+				if m.excludedCount > 0 {
+					gologger.Verbose().Msgf("Excluded %d permutations using rules", m.excludedCount)
+				}
+				if m.Options.DomainChan != nil && m.collapsedCount > 0 {
+					gologger.Info().Msgf("Collapsed %d duplicate inputs after normalization", m.collapsedCount)
+				}
 				return nil
 			}
 
 			// Skip if limit reached
 			if m.Options.Limit > 0 && m.payloadCount >= m.Options.Limit {
+				cancel()
 				continue
 			}
 
 			// Skip if max size reached
 			if m.Options.MaxSize > 0 && remainingSize <= 0 {
+				cancel()
 				continue
 			}
 
@@ -211,6 +456,9 @@ func (m *Mutator) ExecuteWithWriter(ctx context.Context, writer io.Writer) error
 				remainingSize -= n
 			}
 			m.payloadCount++
+			if m.Options.Limit > 0 && m.payloadCount >= m.Options.Limit {
+				cancel()
+			}
 		}
 	}
 }
@@ -226,34 +474,159 @@ func (m *Mutator) ExecuteWithWriterLegacy(writer io.Writer) error {
 func (m *Mutator) EstimateCount() int {
 	counter := 0
 	for _, v := range m.Inputs {
-		varMap := getSampleMap(v.GetMap(), m.Options.Payloads)
-		for _, pattern := range m.Options.Patterns {
-			if err := checkMissing(pattern, varMap); err == nil {
+		counter += m.estimateInput(v)
+	}
+	return counter
+}
+
+// executeInput generates permutations of a single input using all patterns
+func (m *Mutator) executeInput(ctx context.Context, input *Input, results chan string) {
+	if input.Template != "" {
+		if !m.hasWildcardPayload() {
+			// only streamed template inputs reach here since others are checked in New
+			if !m.wildcardWarned {
+				gologger.Warning().Msgf("wildcard payload '%s' is empty, skipping template inputs like %s", m.Options.WildcardPayload, input.Template)
+				m.wildcardWarned = true
+			}
+			return
+		}
+		p := m.templatePattern(input)
+		m.permute(ctx, p, input, p.template, results)
+		return
+	}
+	for _, v := range m.getLevels(input) {
+		varMap := m.getSampleMap(v)
+		for _, pattern := range m.patterns {
+			// Check for cancellation at the pattern level
+			select {
+			case <-ctx.Done():
+				return
+			default:
+			}
+
+			if err := checkMissing(pattern.template, varMap); err == nil {
+				statement := Replace(pattern.template, v.GetMap())
+				m.permute(ctx, pattern, v, statement, results)
+			} else if m.isAnnotationVars(getMissingVars(pattern.template, varMap)) {
+				// patterns using annotations are meant only for inputs having them
+				gologger.Verbose().Msgf("pattern '%s' skipped for input without annotations: %v", pattern.raw, err)
+			} else if !pattern.optional && v.prefix == "" {
+				// variants with optional segments and deeper levels are silently omitted if their variables are missing
+				gologger.Warning().Msgf("pattern '%s' has missing variables: %v, skipping", pattern.raw, err)
+			}
+		}
+	}
+	if !m.mutateLevels(input, func(value string) bool {
+		return m.sendResult(ctx, input, value, results)
+	}) {
+		return
+	}
+	if !m.swapTokens(input, func(value string) bool {
+		return m.sendResult(ctx, input, value, results)
+	}) {
+		return
+	}
+	m.extrapolateSeries(input, func(value string) bool {
+		return m.sendResult(ctx, input, value, results)
+	})
+}
+
+// estimateInput estimates number of permutations of a single input
+func (m *Mutator) estimateInput(input *Input) int {
+	if input.Template != "" {
+		p := m.templatePattern(input)
+		if m.maxkeyLenInBytes < len(p.template) {
+			m.maxkeyLenInBytes = len(p.template)
+		}
+		return m.countPattern(p, p.template)
+	}
+	counter := 0
+	for _, v := range m.getLevels(input) {
+		varMap := m.getSampleMap(v)
+		for _, pattern := range m.patterns {
+			if err := checkMissing(pattern.template, varMap); err == nil {
 				// if say patterns is {{sub}}.{{sub1}}-{{word}}.{{root}}
 				// and input domain is api.scanme.sh its clear that {{sub1}} here will be empty/missing
 				// in such cases `alterx` silently skips that pattern for that specific input
 				// this way user can have a long list of patterns but they are only used if all required data is given (much like self-contained templates)
-				statement := Replace(pattern, v.GetMap())
-				bin := unsafeToBytes(statement)
-				if m.maxkeyLenInBytes < len(bin) {
-					m.maxkeyLenInBytes = len(bin)
-				}
-				varsUsed := getAllVars(statement)
-				if len(varsUsed) == 0 {
-					counter += 1
-				} else {
-					tmpCounter := 1
-					for _, word := range varsUsed {
-						tmpCounter *= len(m.Options.Payloads[word])
-					}
-					counter += tmpCounter
+				// optional segments are already expanded into separate templates
+				// so each variant that can be used for input is counted separately
+				statement := Replace(pattern.template, v.GetMap())
+				if keyLen := len(v.prefix) + len(unsafeToBytes(statement)); m.maxkeyLenInBytes < keyLen {
+					m.maxkeyLenInBytes = keyLen
 				}
+				counter += m.countPattern(pattern, statement)
 			}
 		}
 	}
+	count := func(value string) bool {
+		if m.maxkeyLenInBytes < len(value) {
+			m.maxkeyLenInBytes = len(value)
+		}
+		counter++
+		return true
+	}
+	m.mutateLevels(input, count)
+	m.swapTokens(input, count)
+	m.extrapolateSeries(input, count)
 	return counter
 }
 
+// countPattern returns number of permutations of statement created from pattern
+func (m *Mutator) countPattern(p *pattern, statement string) int {
+	names := m.getPayloadNames(statement)
+	mode := m.getMode(p)
+	var lengths []int
+	if p.distinct || p.ordered {
+		// instances of same payload are counted together using their constraints
+		instances := getInstances(names)
+		for base, vars := range instances {
+			lengths = append(lengths, countInstances(m.getPayload(p, base).Len(), len(vars), p.distinct, p.ordered))
+		}
+		for _, v := range names {
+			if _, number := splitInstance(v); number == 0 {
+				lengths = append(lengths, m.getPayload(p, v).Len())
+			}
+		}
+	} else {
+		for _, v := range names {
+			lengths = append(lengths, m.getPayload(p, v).Len())
+		}
+	}
+	return countPermutations(mode, lengths)
+}
+
+// hasWildcardPayload returns true if payload filling wildcards of template inputs has values
+func (m *Mutator) hasWildcardPayload() bool {
+	return m.getPayload(&pattern{}, m.Options.WildcardPayload).Len() > 0
+}
+
+// templatePattern returns pattern of template input where every wildcard is replaced
+// by an instance of wildcard payload (ex: prod.*.*.example.com => prod.{{word#1}}.{{word#2}}.example.com)
+// patterns and level mutations are not applied to template inputs
+func (m *Mutator) templatePattern(input *Input) *pattern {
+	name := m.Options.WildcardPayload
+	slots := strings.Count(input.Template, WildcardSlot)
+	template := input.Template
+	for i := 1; i <= slots; i++ {
+		variable := name
+		if slots > 1 {
+			variable += InstanceSeparator + strconv.Itoa(i)
+		}
+		template = strings.Replace(template, WildcardSlot, ParenthesisOpen+variable+ParenthesisClose, 1)
+	}
+	return &pattern{raw: input.Template, template: template}
+}
+
+// getLevels returns input along with inputs viewed at each of its deeper labels
+// if per level mode is enabled
+func (m *Mutator) getLevels(input *Input) []*Input {
+	if !m.Options.PerLevel {
+		return []*Input{input}
+	}
+	return append([]*Input{input}, input.levels()...)
+}
+
 // DryRun executes payloads without storing and returns number of payloads created
 // this value is also stored in variable and can be accessed via getter `PayloadCount`
 func (m *Mutator) DryRun() int {
@@ -265,47 +638,197 @@ func (m *Mutator) DryRun() int {
 	return m.payloadCount
 }
 
-// clusterBomb calculates all payloads of clusterbomb attack and sends them to result channel
+// permute calculates all payloads of pattern using its attack mode and sends them to result channel
 // It respects context cancellation to allow early termination
-func (m *Mutator) clusterBomb(ctx context.Context, template string, results chan string) {
+func (m *Mutator) permute(ctx context.Context, p *pattern, input *Input, template string, results chan string) {
 	// Early Exit: this is what saves clusterBomb from stackoverflows and reduces
 	// n*len(n) iterations and n recursions
-	varsUsed := getAllVars(template)
+	varsUsed := m.getPayloadNames(template)
 	if len(varsUsed) == 0 {
-		// clusterBomb is not required
+		// permutations are not required
 		// just send existing template as result and exit
-		select {
-		case results <- template:
-		case <-ctx.Done():
-		}
+		m.sendResult(ctx, input, input.prefix+template, results)
 		return
 	}
-	payloadSet := map[string][]string{}
+	payloadSet := map[string]Generator{}
 	// instead of sending all payloads only send payloads that are used
 	// in template/statement
+	mode := m.getMode(p)
 	leftmostPart, _, _ := strings.Cut(template, ".")
 	for _, v := range varsUsed {
-		payloadSet[v] = []string{}
-		for _, word := range m.Options.Payloads[v] {
+		generator := m.getPayload(p, v)
+		words, ok := generator.(Wordlist)
+		if !ok || mode == PitchforkMode {
+			// generators like ranges and tuples are evaluated lazily and used as is
+			// pitchfork payloads are never filtered to keep their values aligned
+			payloadSet[v] = generator
+			continue
+		}
+		filtered := Wordlist{}
+		for _, word := range words {
 			if !strings.HasPrefix(leftmostPart, word) && !strings.HasSuffix(leftmostPart, word) {
 				// skip all words that are already present in leftmost part, it is highly unlikely
 				// we will ever find api-api.example.com
-				payloadSet[v] = append(payloadSet[v], word)
+				filtered = append(filtered, word)
 			}
 		}
+		payloadSet[v] = filtered
 	}
-	payloads := NewIndexMap(payloadSet)
-	// in clusterBomb attack no of payloads generated are
-	// len(first_set)*len(second_set)*len(third_set)....
+	payloads := NewGeneratorIndexMap(payloadSet)
+	isAllowed := p.instanceFilter(getInstances(varsUsed), payloadSet)
+	templateVars := getAllVars(template)
 	callbackFunc := func(varMap map[string]interface{}) bool {
-		select {
-		case results <- Replace(template, varMap):
+		if isAllowed != nil && !isAllowed(varMap) {
 			return true
-		case <-ctx.Done():
-			return false
+		}
+		for _, v := range varsUsed {
+			if tuples, ok := payloadSet[v].(Tuples); ok {
+				tuples.Expand(v, varMap[v].(string), varMap)
+			}
+		}
+		if m.Options.Rules != nil && m.Options.Rules.DistinctFromSub && hasSubValue(varMap, templateVars, input.Sub) {
+			m.excludedCount++
+			return true
+		}
+		return m.sendResult(ctx, input, input.prefix+Replace(template, varMap), results)
+	}
+	switch mode {
+	case PitchforkMode:
+		// no of payloads generated are min(len(first_set), len(second_set)....)
+		Pitchfork(payloads, callbackFunc)
+	case SniperMode:
+		// no of payloads generated are 1 + (len(first_set)-1) + (len(second_set)-1)....
+		Sniper(payloads, callbackFunc)
+	default:
+		// in clusterBomb attack no of payloads generated are
+		// len(first_set)*len(second_set)*len(third_set)....
+		ClusterBomb(payloads, callbackFunc, []string{})
+	}
+}
+
+// sendResult sends candidate to result channel in configured idn form if it is a valid hostname
+// and satisfies exclusion rules
+// it returns false if context was cancelled
+func (m *Mutator) sendResult(ctx context.Context, input *Input, value string, results chan string) bool {
+	// hostnames are case insensitive so candidates changed by case filters
+	// are lowercased to avoid emitting duplicates differing only in case
+	value = strings.ToLower(value)
+	idn := isIDN(value)
+	if idn {
+		// internationalized candidates are validated in punycode form
+		ascii, err := toASCIIName(value)
+		if err != nil && m.Options.Validation != ValidationOff {
+			m.rejections[RejectInvalidIDN]++
+			return true
+		} else if err == nil {
+			value = ascii
+		}
+	}
+	value, reason := validateCandidate(value, m.Options.Validation)
+	if reason != "" {
+		m.rejections[reason]++
+		return true
+	}
+	if idn && m.Options.IDNOutput == IDNUnicode {
+		value = toUnicodeName(value)
+	}
+	if m.Options.Rules != nil && !m.Options.Rules.IsAllowed(value, input.Root) {
+		m.excludedCount++
+		return true
+	}
+	select {
+	case results <- value:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// getMode returns attack mode of pattern
+func (m *Mutator) getMode(p *pattern) string {
+	if p.mode != "" {
+		return p.mode
+	}
+	if m.Options.Mode != "" {
+		return m.Options.Mode
+	}
+	return ClusterBombMode
+}
+
+// getPayload returns payload generator of variable available to pattern
+// payloads local to pattern (ex: inline alternations) take precedence
+// instances of repeated variables use payload of their name (ex: word#1 => word)
+func (m *Mutator) getPayload(p *pattern, name string) Generator {
+	name, _ = splitInstance(name)
+	if generator, ok := p.payloads[name]; ok {
+		return generator
+	}
+	if r, ok := m.Options.Ranges[name]; ok {
+		return r
+	}
+	if tuples, ok := m.Options.Tuples[name]; ok {
+		return tuples
+	}
+	return Wordlist(m.Options.Payloads[name])
+}
+
+// getPayloadNames returns unique names of payloads used in template
+// fields of tuples are resolved to name of tuple (ex: {{env.abbr}} => env)
+func (m *Mutator) getPayloadNames(template string) []string {
+	var names []string
+	for _, v := range getAllVars(template) {
+		if name, _, ok := strings.Cut(v, TupleFieldSeparator); ok {
+			if _, exists := m.Options.Tuples[name]; exists {
+				v = name
+			}
+		}
+		names = append(names, v)
+	}
+	return sliceutil.Dedupe(names)
+}
+
+// getSampleMap returns a sample map containing input variables and all payload variables
+func (m *Mutator) getSampleMap(input *Input) map[string]interface{} {
+	inputVars := input.GetMap()
+	sMap := make(map[string]interface{}, len(inputVars)+len(m.payloadVars))
+	for k, v := range inputVars {
+		sMap[k] = v
+	}
+	for k, v := range m.payloadVars {
+		sMap[k] = v
+	}
+	return sMap
+}
+
+// preparePayloadVars collects variables of payloads, ranges, tuples and instances
+// available to patterns. they do not depend on input so are collected only once
+func (m *Mutator) preparePayloadVars() {
+	m.payloadVars = getSampleMap(nil, m.Options.Payloads)
+	for k := range m.Options.Ranges {
+		m.payloadVars[k] = "temp"
+	}
+	for k, v := range m.Options.Tuples {
+		for _, field := range v.Fields() {
+			m.payloadVars[k+TupleFieldSeparator+field] = "temp"
+		}
+	}
+	for _, p := range m.patterns {
+		for k := range p.payloads {
+			m.payloadVars[k] = "temp"
+		}
+	}
+	for _, p := range m.patterns {
+		for _, v := range getAllVars(p.template) {
+			// instances of repeated variables are available if their payload is available
+			// instances of tuples are not supported
+			if _, number := splitInstance(v); number > 0 {
+				generator := m.getPayload(p, v)
+				if _, isTuple := generator.(Tuples); !isTuple && generator.Len() > 0 {
+					m.payloadVars[v] = "temp"
+				}
+			}
 		}
 	}
-	ClusterBomb(payloads, callbackFunc, []string{})
 }
 
 // prepareInputs processes and validates all input domains
@@ -313,19 +836,29 @@ func (m *Mutator) prepareInputs() error {
 	var errors []string
 	var allInputs []*Input
 
+	m.seenInputs = map[string]*Input{}
+	m.collapsedCount = 0
 	for _, domain := range m.Options.Domains {
-		input, err := NewInput(domain)
+		inputs, err := m.parseInputs(domain)
 		if err != nil {
 			errors = append(errors, fmt.Sprintf("%s: %v", domain, err))
-			continue
 		}
-		allInputs = append(allInputs, input)
+		for _, input := range inputs {
+			if m.isDuplicateInput(input) {
+				// annotations of duplicates are merged into first occurrence
+				mergeVars(m.seenInputs[input.Hostname()], input)
+				continue
+			}
+			m.addSeries(input)
+			allInputs = append(allInputs, input)
+		}
 	}
 
 	m.Inputs = allInputs
 
 	// If ALL inputs failed, return error
-	if len(allInputs) == 0 {
+	// inputs may still be read from channel if streaming
+	if len(allInputs) == 0 && m.Options.DomainChan == nil {
 		if len(errors) > 0 {
 			return fmt.Errorf("all %d input domains failed to parse: %s", len(m.Options.Domains), strings.Join(errors, "; "))
 		}
@@ -336,46 +869,152 @@ func (m *Mutator) prepareInputs() error {
 	if len(errors) > 0 {
 		gologger.Warning().Msgf("failed to parse %d/%d domains: %s", len(errors), len(m.Options.Domains), strings.Join(errors, "; "))
 	}
+	if m.collapsedCount > 0 {
+		gologger.Info().Msgf("Collapsed %d duplicate inputs after normalization", m.collapsedCount)
+	}
 
 	return nil
 }
 
+// parseInputs parses input line in configured format into inputs along with their variables
+// valid inputs are returned even if some inputs of line are invalid
+func (m *Mutator) parseInputs(line string) ([]*Input, error) {
+	records, err := m.parser.Parse(line)
+	if err != nil {
+		return nil, err
+	}
+	var inputs []*Input
+	var errs []string
+	for _, record := range records {
+		input, err := NewInputWithSuffixList(NormalizeDomain(record.Domain), m.Options.SuffixList)
+		if err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		if len(record.Vars) > 0 {
+			input.Vars = record.Vars
+			for k := range record.Vars {
+				m.annotations[k] = struct{}{}
+			}
+		}
+		input.tokenizer = m.Options.Tokenizer
+		inputs = append(inputs, input)
+	}
+	if len(errs) > 0 {
+		return inputs, fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+	return inputs, nil
+}
+
+// isDuplicateInput returns true if input with same canonical hostname was already seen
+// while streaming, hostnames are kept in disk backed storage instead of memory
+func (m *Mutator) isDuplicateInput(input *Input) bool {
+	hostname := input.Hostname()
+	_, seen := m.seenInputs[hostname]
+	switch {
+	case seen:
+	case m.streamedInputs != nil:
+		seen = !m.streamedInputs.Upsert(hostname)
+	default:
+		m.seenInputs[hostname] = input
+	}
+	if seen {
+		m.collapsedCount++
+	}
+	return seen
+}
+
+// isAnnotationVars returns true if all variables can only be provided by extra variables of inputs
+// i.e. they are not builtin, payload, range or tuple names and at least one input has them
+func (m *Mutator) isAnnotationVars(vars []string) bool {
+	for _, v := range vars {
+		name := strings.SplitN(v, TupleFieldSeparator, 2)[0]
+		if reservedVarRegex.MatchString(v) || m.isPayloadName(name) {
+			return false
+		}
+		if _, ok := m.annotations[v]; !ok {
+			return false
+		}
+	}
+	return len(vars) > 0
+}
+
+// isPayloadName returns true if name is a payload, range or tuple
+func (m *Mutator) isPayloadName(name string) bool {
+	_, isPayload := m.Options.Payloads[name]
+	_, isRange := m.Options.Ranges[name]
+	_, isTuple := m.Options.Tuples[name]
+	return isPayload || isRange || isTuple
+}
+
+// mergeVars adds variables of duplicate input missing from its first occurrence
+func mergeVars(first *Input, duplicate *Input) {
+	if first == nil || len(duplicate.Vars) == 0 {
+		return
+	}
+	if first.Vars == nil {
+		first.Vars = map[string]string{}
+	}
+	for k, value := range duplicate.Vars {
+		if _, ok := first.Vars[k]; !ok {
+			first.Vars[k] = value
+		}
+	}
+}
+
 // validates all patterns by compiling them
 func (m *Mutator) validatePatterns() error {
-	for _, v := range m.Options.Patterns {
-		// check if all placeholders are correctly used and are valid
-		if _, err := fasttemplate.NewTemplate(v, ParenthesisOpen, ParenthesisClose); err != nil {
-			return err
+	patterns, err := compilePatterns(m.Options.Patterns)
+	if err != nil {
+		return err
+	}
+	m.patterns = patterns
+	for _, p := range patterns {
+		// pitchfork and sniper do not combine all values of instances
+		// so constraints on them cannot be counted or applied consistently
+		if (p.distinct || p.ordered) && m.getMode(p) != ClusterBombMode {
+			return fmt.Errorf("pattern '%s': `%v` and `%v` directives are only supported in %v mode", p.raw, DistinctDirective, OrderedDirective, ClusterBombMode)
 		}
 	}
 	return nil
 }
 
-// enrichPayloads extract possible words and adds them to default wordlist
-func (m *Mutator) enrichPayloads() {
-	var temp bytes.Buffer
+// enrichPayloads extract possible words from inputs and adds the ones passing
+// thresholds of enrichment to payload of enrichment key. words of corpus (if not nil)
+// are added if they are not stop words and pass higher min count of corpus words.
+// labels of roots are never added and numbers are not added to payloads since
+// numeric series of inputs are extrapolated instead
+func (m *Mutator) enrichPayloads(corpus *wordCounter) {
+	counter := newWordCounter()
+	exclude := map[string]struct{}{}
 	for _, v := range m.Inputs {
-		temp.WriteString(v.Sub + " ")
-		if len(v.MultiLevel) > 0 {
-			temp.WriteString(strings.Join(v.MultiLevel, " "))
+		counter.add(v.Sub + " " + strings.Join(v.MultiLevel, " "))
+		for _, label := range strings.Split(v.Root, ".") {
+			exclude[label] = struct{}{}
 		}
 	}
-	numbers := extractNumbers.FindAllString(temp.String(), -1)
-	extraWords := extractWords.FindAllString(temp.String(), -1)
-	extraWordsOnly := extractWordsOnly.FindAllString(temp.String(), -1)
-	if len(extraWordsOnly) > 0 {
-		extraWords = append(extraWords, extraWordsOnly...)
-		extraWords = sliceutil.Dedupe(extraWords)
+	enrichment := m.Options.Enrichment
+	words := counter.words(enrichment, exclude)
+	if corpus != nil {
+		for k := range corpusStopWords {
+			exclude[k] = struct{}{}
+		}
+		corpusEnrichment := *enrichment
+		corpusEnrichment.MinCount = max(enrichment.MinCount, DefaultCorpusMinCount)
+		words = sliceutil.Dedupe(append(words, corpus.words(&corpusEnrichment, exclude)...))
+		if enrichment.MaxWords > 0 && len(words) > enrichment.MaxWords {
+			words = words[:enrichment.MaxWords]
+		}
 	}
-
-	if len(m.Options.Payloads["word"]) > 0 {
-		extraWords = append(extraWords, m.Options.Payloads["word"]...)
-		m.Options.Payloads["word"] = sliceutil.Dedupe(extraWords)
+	if len(words) == 0 {
+		return
 	}
-	if len(m.Options.Payloads["number"]) > 0 {
-		numbers = append(numbers, m.Options.Payloads["number"]...)
-		m.Options.Payloads["number"] = sliceutil.Dedupe(numbers)
+	if m.Options.Payloads == nil {
+		m.Options.Payloads = map[string][]string{}
 	}
+	key := enrichment.Key
+	m.Options.Payloads[key] = sliceutil.Dedupe(append(m.Options.Payloads[key], words...))
+	gologger.Verbose().Msgf("Enriched payload '%s' with %d words from inputs", key, len(words))
 }
 
 // PayloadCount returns total estimated payloads count
@@ -386,6 +1025,20 @@ func (m *Mutator) PayloadCount() int {
 	return m.payloadCount
 }
 
+// RejectedCount returns number of invalid permutations dropped by validation
+func (m *Mutator) RejectedCount() int {
+	counter := 0
+	for _, v := range m.rejections {
+		counter += v
+	}
+	return counter
+}
+
+// CollapsedCount returns number of duplicate inputs dropped after normalization
+func (m *Mutator) CollapsedCount() int {
+	return m.collapsedCount
+}
+
 // Time returns time taken to create permutations in seconds
 func (m *Mutator) Time() string {
 	return fmt.Sprintf("%.4fs", m.timeTaken.Seconds())
@@ -3,8 +3,11 @@ package alterx
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"io"
 	"math"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -26,8 +29,7 @@ var testConfig = Config{
 
 func TestMutatorCount(t *testing.T) {
 	opts := &Options{
-		Domains:       []string{"api.scanme.sh", "chaos.scanme.sh", "nuclei.scanme.sh", "cloud.nuclei.scanme.sh"},
-		DedupeResults: true,
+		Domains: []string{"api.scanme.sh", "chaos.scanme.sh", "nuclei.scanme.sh", "cloud.nuclei.scanme.sh"},
 	}
 	opts.Patterns = testConfig.Patterns
 	opts.Payloads = testConfig.Payloads
@@ -40,9 +42,8 @@ func TestMutatorCount(t *testing.T) {
 
 func TestMutatorResults(t *testing.T) {
 	opts := &Options{
-		Domains:       []string{"api.scanme.sh", "chaos.scanme.sh", "nuclei.scanme.sh", "cloud.nuclei.scanme.sh"},
-		DedupeResults: true,
-		MaxSize:       math.MaxInt,
+		Domains: []string{"api.scanme.sh", "chaos.scanme.sh", "nuclei.scanme.sh", "cloud.nuclei.scanme.sh"},
+		MaxSize: math.MaxInt,
 	}
 	opts.Patterns = testConfig.Patterns
 	opts.Payloads = testConfig.Payloads
@@ -117,12 +118,11 @@ func TestMutatorWithDefaultConfig(t *testing.T) {
 func TestMutatorLimit(t *testing.T) {
 	t.Run("respect limit", func(t *testing.T) {
 		opts := &Options{
-			Domains:       []string{"example.com"},
-			Patterns:      []string{"{{word}}.{{root}}"},
-			Payloads:      map[string][]string{"word": {"a", "b", "c", "d", "e"}},
-			Limit:         3,
-			DedupeResults: true,
-			MaxSize:       math.MaxInt,
+			Domains:  []string{"example.com"},
+			Patterns: []string{"{{word}}.{{root}}"},
+			Payloads: map[string][]string{"word": {"a", "b", "c", "d", "e"}},
+			Limit:    3,
+			MaxSize:  math.MaxInt,
 		}
 		m, err := New(opts)
 		require.NoError(t, err)
@@ -139,11 +139,10 @@ func TestMutatorLimit(t *testing.T) {
 func TestMutatorMaxSize(t *testing.T) {
 	t.Run("respect max size", func(t *testing.T) {
 		opts := &Options{
-			Domains:       []string{"example.com"},
-			Patterns:      []string{"{{word}}.{{root}}"},
-			Payloads:      map[string][]string{"word": {"a", "b", "c", "d", "e"}},
-			MaxSize:       50, // Small size to trigger limit
-			DedupeResults: true,
+			Domains:  []string{"example.com"},
+			Patterns: []string{"{{word}}.{{root}}"},
+			Payloads: map[string][]string{"word": {"a", "b", "c", "d", "e"}},
+			MaxSize:  50, // Small size to trigger limit
 		}
 		m, err := New(opts)
 		require.NoError(t, err)
@@ -159,12 +158,11 @@ func TestMutatorMaxSize(t *testing.T) {
 func TestMutatorEnrich(t *testing.T) {
 	t.Run("enrich extracts words from domains", func(t *testing.T) {
 		opts := &Options{
-			Domains:       []string{"api123.example.com", "dev456.example.com"},
-			Patterns:      []string{"{{word}}.{{root}}"},
-			Payloads:      map[string][]string{"word": {"base"}},
-			Enrich:        true,
-			DedupeResults: true,
-			MaxSize:       math.MaxInt,
+			Domains:  []string{"api123.example.com", "dev456.example.com"},
+			Patterns: []string{"{{word}}.{{root}}"},
+			Payloads: map[string][]string{"word": {"base"}},
+			Enrich:   true,
+			MaxSize:  math.MaxInt,
 		}
 		m, err := New(opts)
 		require.NoError(t, err)
@@ -174,16 +172,124 @@ func TestMutatorEnrich(t *testing.T) {
 		// Should contain extracted words
 		require.Greater(t, len(m.Options.Payloads["word"]), 1)
 	})
+
+	t.Run("enrich key and thresholds", func(t *testing.T) {
+		opts := &Options{
+			Domains:    []string{"api-prod.example.com", "web-prod.example.com", "api-dev.example.com", "f3a9c1e7b2.example.com"},
+			Patterns:   []string{"{{enriched}}.{{suffix}}"},
+			Payloads:   map[string][]string{"word": {"base"}},
+			Enrich:     true,
+			Enrichment: &Enrichment{Key: "enriched", MinCount: 2},
+			MaxSize:    math.MaxInt,
+		}
+		m, err := New(opts)
+		require.NoError(t, err)
+		require.Equal(t, []string{"base"}, m.Options.Payloads["word"])
+		require.Equal(t, []string{"api", "prod"}, m.Options.Payloads["enriched"])
+	})
+
+	t.Run("enrich without word payload", func(t *testing.T) {
+		m, err := New(&Options{
+			Domains:  []string{"api-staging.example.com"},
+			Patterns: []string{"{{word}}.{{suffix}}"},
+			Payloads: map[string][]string{"number": {"1"}},
+			Enrich:   true,
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{"api", "staging"}, m.Options.Payloads["word"])
+	})
+
+	t.Run("enrich keeps default and caller payloads", func(t *testing.T) {
+		defaults := copyPayloads(DefaultConfig.Payloads)
+		_, err := New(&Options{
+			Domains:    []string{"api-staging.example.com"},
+			Enrich:     true,
+			Enrichment: &Enrichment{Key: "enriched"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, defaults, DefaultConfig.Payloads)
+
+		// values with spare capacity are not overwritten by appended words
+		words := make([]string, 1, 4)
+		words[0] = "base"
+		payloads := map[string][]string{"word": words}
+		_, err = New(&Options{
+			Domains:  []string{"api-staging.example.com"},
+			Patterns: []string{"{{word}}.{{suffix}}"},
+			Payloads: payloads,
+			Enrich:   true,
+		})
+		require.NoError(t, err)
+		require.Equal(t, map[string][]string{"word": {"base"}}, payloads)
+		require.Equal(t, []string{"base", ""}, words[:2])
+	})
+
+	t.Run("enrich key conflicts", func(t *testing.T) {
+		for _, key := range []string{"sub", "node"} {
+			_, err := New(&Options{
+				Domains:    []string{"api.example.com"},
+				Patterns:   []string{"{{word}}.{{suffix}}"},
+				Payloads:   map[string][]string{"word": {"dev"}},
+				Ranges:     map[string]*Range{"node": {Start: 1, End: 3}},
+				Enrich:     true,
+				Enrichment: &Enrichment{Key: key},
+			})
+			require.Error(t, err, key)
+		}
+	})
+}
+
+func TestMutatorEnrichFrom(t *testing.T) {
+	tmpDir := t.TempDir()
+	bundle := `function checkout(cart) { return fetch("https://payments-api.example.com/v2/checkout", cart) }
+var cdn = "assets.example.com"; var other = "tracker.other.net"; window.checkout = checkout; // example.com`
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "app.js"), []byte(bundle), 0644))
+
+	opts := &Options{
+		Domains:    []string{"www.example.com"},
+		Patterns:   []string{"{{sub}}.{{suffix}}"},
+		Payloads:   map[string][]string{"word": {"dev"}},
+		EnrichFrom: []string{tmpDir},
+		MaxSize:    math.MaxInt,
+	}
+	m, err := New(opts)
+	require.NoError(t, err)
+	require.True(t, m.Options.Enrich)
+	require.Equal(t, []string{"www.example.com", "payments-api.example.com", "assets.example.com"}, m.Options.Domains)
+	// only words of found subdomains are enriched by default
+	require.ElementsMatch(t, []string{"dev", "www", "payments", "api", "assets"}, m.Options.Payloads["word"])
+	require.ElementsMatch(t, []string{"www.example.com", "payments-api.example.com", "assets.example.com"}, collectResults(m))
+
+	t.Run("corpus words", func(t *testing.T) {
+		m, err := New(&Options{
+			Domains:    []string{"www.example.com"},
+			Patterns:   []string{"{{sub}}.{{suffix}}"},
+			Payloads:   map[string][]string{"word": {"dev"}},
+			EnrichFrom: []string{tmpDir},
+			Enrichment: &Enrichment{CorpusWords: true},
+		})
+		require.NoError(t, err)
+		// stop words, rare words and labels of roots are skipped
+		require.ElementsMatch(t, []string{"dev", "www", "payments", "api", "assets", "checkout"}, m.Options.Payloads["word"])
+	})
+
+	t.Run("missing source", func(t *testing.T) {
+		_, err := New(&Options{
+			Domains:    []string{"www.example.com"},
+			Patterns:   []string{"{{sub}}.{{suffix}}"},
+			EnrichFrom: []string{filepath.Join(tmpDir, "missing")},
+		})
+		require.Error(t, err)
+	})
 }
 
 func TestMutatorContext(t *testing.T) {
 	t.Run("context cancellation", func(t *testing.T) {
 		opts := &Options{
-			Domains:       []string{"example.com"},
-			Patterns:      []string{"{{word}}.{{root}}"},
-			Payloads:      map[string][]string{"word": generateLargePayload(100)},
-			DedupeResults: true,
-			MaxSize:       math.MaxInt,
+			Domains:  []string{"example.com"},
+			Patterns: []string{"{{word}}.{{root}}"},
+			Payloads: map[string][]string{"word": generateLargePayload(100)},
+			MaxSize:  math.MaxInt,
 		}
 		m, err := New(opts)
 		require.NoError(t, err)
@@ -199,11 +305,10 @@ func TestMutatorContext(t *testing.T) {
 
 	t.Run("context timeout", func(t *testing.T) {
 		opts := &Options{
-			Domains:       []string{"example.com"},
-			Patterns:      []string{"{{word}}.{{root}}"},
-			Payloads:      map[string][]string{"word": generateLargePayload(1000)},
-			DedupeResults: true,
-			MaxSize:       math.MaxInt,
+			Domains:  []string{"example.com"},
+			Patterns: []string{"{{word}}.{{root}}"},
+			Payloads: map[string][]string{"word": generateLargePayload(1000)},
+			MaxSize:  math.MaxInt,
 		}
 		m, err := New(opts)
 		require.NoError(t, err)
@@ -225,8 +330,7 @@ func TestMutatorDeduplication(t *testing.T) {
 			Domains:  []string{"example.com"},
 			Patterns: []string{"{{word}}.{{root}}", "{{word}}.{{root}}"}, // Duplicate pattern
 			Payloads: map[string][]string{"word": {"api"}},
-			DedupeResults: true,
-			MaxSize:       math.MaxInt,
+			MaxSize:  math.MaxInt,
 		}
 		m, err := New(opts)
 		require.NoError(t, err)
@@ -240,12 +344,24 @@ func TestMutatorDeduplication(t *testing.T) {
 		require.Equal(t, 1, len(results))
 	})
 
-	t.Run("without deduplication", func(t *testing.T) {
+	t.Run("deduplication by default", func(t *testing.T) {
 		opts := &Options{
 			Domains:  []string{"example.com"},
 			Patterns: []string{"{{word}}.{{root}}", "{{word}}.{{root}}"}, // Duplicate pattern
 			Payloads: map[string][]string{"word": {"api"}},
-			DedupeResults: false,
+			MaxSize:  math.MaxInt,
+		}
+		m, err := New(opts)
+		require.NoError(t, err)
+		require.Equal(t, []string{"api.example.com"}, collectResults(m))
+	})
+
+	t.Run("without deduplication", func(t *testing.T) {
+		opts := &Options{
+			Domains:       []string{"example.com"},
+			Patterns:      []string{"{{word}}.{{root}}", "{{word}}.{{root}}"}, // Duplicate pattern
+			Payloads:      map[string][]string{"word": {"api"}},
+			DisableDedupe: true,
 			MaxSize:       math.MaxInt,
 		}
 		m, err := New(opts)
@@ -263,11 +379,10 @@ func TestMutatorDeduplication(t *testing.T) {
 
 func TestMutatorDryRun(t *testing.T) {
 	opts := &Options{
-		Domains:       []string{"example.com"},
-		Patterns:      []string{"{{word}}.{{root}}"},
-		Payloads:      map[string][]string{"word": {"a", "b", "c"}},
-		DedupeResults: true,
-		MaxSize:       math.MaxInt,
+		Domains:  []string{"example.com"},
+		Patterns: []string{"{{word}}.{{root}}"},
+		Payloads: map[string][]string{"word": {"a", "b", "c"}},
+		MaxSize:  math.MaxInt,
 	}
 	m, err := New(opts)
 	require.NoError(t, err)
@@ -278,11 +393,10 @@ func TestMutatorDryRun(t *testing.T) {
 
 func TestMutatorPayloadCount(t *testing.T) {
 	opts := &Options{
-		Domains:       []string{"example.com"},
-		Patterns:      []string{"{{word}}.{{root}}"},
-		Payloads:      map[string][]string{"word": {"a", "b"}},
-		DedupeResults: true,
-		MaxSize:       math.MaxInt,
+		Domains:  []string{"example.com"},
+		Patterns: []string{"{{word}}.{{root}}"},
+		Payloads: map[string][]string{"word": {"a", "b"}},
+		MaxSize:  math.MaxInt,
 	}
 	m, err := New(opts)
 	require.NoError(t, err)
@@ -299,11 +413,10 @@ func TestMutatorPayloadCount(t *testing.T) {
 
 func TestMutatorSkipsInvalidDomains(t *testing.T) {
 	opts := &Options{
-		Domains:       []string{"valid.example.com", ".invalid", "another.example.com"},
-		Patterns:      []string{"{{word}}.{{root}}"},
-		Payloads:      map[string][]string{"word": {"api"}},
-		DedupeResults: true,
-		MaxSize:       math.MaxInt,
+		Domains:  []string{"valid.example.com", ".invalid", "another.example.com"},
+		Patterns: []string{"{{word}}.{{root}}"},
+		Payloads: map[string][]string{"word": {"api"}},
+		MaxSize:  math.MaxInt,
 	}
 	m, err := New(opts)
 	require.NoError(t, err)
@@ -315,16 +428,16 @@ func TestMutatorSkipsInvalidDomains(t *testing.T) {
 	err = m.ExecuteWithWriter(context.Background(), &buff)
 	require.NoError(t, err)
 
+	// both valid inputs generate api.example.com which is deduplicated
 	results := strings.Split(strings.TrimSpace(buff.String()), "\n")
-	require.Equal(t, 2, len(results))
+	require.Equal(t, []string{"api.example.com"}, results)
 }
 
 func TestMutatorNilWriter(t *testing.T) {
 	opts := &Options{
-		Domains:       []string{"example.com"},
-		Patterns:      []string{"{{word}}.{{root}}"},
-		Payloads:      map[string][]string{"word": {"api"}},
-		DedupeResults: true,
+		Domains:  []string{"example.com"},
+		Patterns: []string{"{{word}}.{{root}}"},
+		Payloads: map[string][]string{"word": {"api"}},
 	}
 	m, err := New(opts)
 	require.NoError(t, err)
@@ -337,11 +450,10 @@ func TestMutatorNilWriter(t *testing.T) {
 func TestMutatorSkipsHyphenPrefixedResults(t *testing.T) {
 	// This tests that results starting with "-" are skipped (invalid domains)
 	opts := &Options{
-		Domains:       []string{"example.com"},
-		Patterns:      []string{"{{word}}.{{root}}"},
-		Payloads:      map[string][]string{"word": {"-invalid", "valid"}},
-		DedupeResults: true,
-		MaxSize:       math.MaxInt,
+		Domains:  []string{"example.com"},
+		Patterns: []string{"{{word}}.{{root}}"},
+		Payloads: map[string][]string{"word": {"-invalid", "valid"}},
+		MaxSize:  math.MaxInt,
 	}
 	m, err := New(opts)
 	require.NoError(t, err)
@@ -355,8 +467,841 @@ func TestMutatorSkipsHyphenPrefixedResults(t *testing.T) {
 	require.Contains(t, results, "valid.example.com")
 }
 
+func TestMutatorFilters(t *testing.T) {
+	t.Run("filters are applied to input and payload variables", func(t *testing.T) {
+		opts := &Options{
+			Domains:  []string{"api-gateway.example.com"},
+			Patterns: []string{"{{sub|replace:-:}}{{number|pad:2}}.{{suffix}}", "{{sub|last}}.{{suffix}}"},
+			Payloads: map[string][]string{"number": {"1", "2"}},
+			MaxSize:  math.MaxInt,
+		}
+		m, err := New(opts)
+		require.NoError(t, err)
+		require.Equal(t, 3, m.EstimateCount())
+
+		var buff bytes.Buffer
+		err = m.ExecuteWithWriter(context.Background(), &buff)
+		require.NoError(t, err)
+
+		results := strings.Split(strings.TrimSpace(buff.String()), "\n")
+		require.ElementsMatch(t, []string{"apigateway01.example.com", "apigateway02.example.com", "gateway.example.com"}, results)
+	})
+
+	t.Run("case filters do not change case of results", func(t *testing.T) {
+		m, err := New(&Options{
+			Domains:  []string{"api.example.com"},
+			Patterns: []string{"{{sub|upper}}.{{suffix}}", "{{sub|title}}-{{word}}.{{suffix}}", "{{sub}}-{{word}}.{{suffix}}"},
+			Payloads: map[string][]string{"word": {"dev"}},
+			MaxSize:  math.MaxInt,
+		})
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{"api.example.com", "api-dev.example.com"}, collectResults(m))
+	})
+
+	t.Run("invalid filter fails validation", func(t *testing.T) {
+		opts := &Options{
+			Domains:  []string{"api.example.com"},
+			Patterns: []string{"{{sub|unknown}}.{{suffix}}"},
+			Payloads: map[string][]string{"word": {"dev"}},
+		}
+		_, err := New(opts)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unknown filter")
+	})
+}
+
+func TestMutatorOptionalSegments(t *testing.T) {
+	t.Run("optional segment is expanded", func(t *testing.T) {
+		opts := &Options{
+			Domains:  []string{"api.example.com"},
+			Patterns: []string{"[{{word}}-]{{sub}}.{{suffix}}"},
+			Payloads: map[string][]string{"word": {"dev", "prod"}},
+			MaxSize:  math.MaxInt,
+		}
+		m, err := New(opts)
+		require.NoError(t, err)
+		require.Equal(t, 3, m.EstimateCount())
+
+		var buff bytes.Buffer
+		err = m.ExecuteWithWriter(context.Background(), &buff)
+		require.NoError(t, err)
+
+		results := strings.Split(strings.TrimSpace(buff.String()), "\n")
+		require.ElementsMatch(t, []string{"dev-api.example.com", "prod-api.example.com", "api.example.com"}, results)
+	})
+
+	t.Run("segment with missing variables is omitted", func(t *testing.T) {
+		opts := &Options{
+			Domains:  []string{"api.example.com"},
+			Patterns: []string{"{{sub}}[.{{sub1}}]-{{word}}.{{root}}"},
+			Payloads: map[string][]string{"word": {"dev"}},
+			MaxSize:  math.MaxInt,
+		}
+		m, err := New(opts)
+		require.NoError(t, err)
+		require.Equal(t, 1, m.EstimateCount())
+
+		var buff bytes.Buffer
+		err = m.ExecuteWithWriter(context.Background(), &buff)
+		require.NoError(t, err)
+		require.Equal(t, "api-dev.example.com", strings.TrimSpace(buff.String()))
+	})
+
+	t.Run("unbalanced segment fails validation", func(t *testing.T) {
+		opts := &Options{
+			Domains:  []string{"api.example.com"},
+			Patterns: []string{"[{{word}}-{{sub}}.{{suffix}}"},
+			Payloads: map[string][]string{"word": {"dev"}},
+		}
+		_, err := New(opts)
+		require.Error(t, err)
+	})
+}
+
+func TestMutatorAlternations(t *testing.T) {
+	opts := &Options{
+		Domains:  []string{"api.example.com", "dev.example.com"},
+		Patterns: []string{"{{sub}}-(dev|stg|prod).{{suffix}}"},
+		Payloads: map[string][]string{"word": {"unused"}},
+		MaxSize:  math.MaxInt,
+	}
+	m, err := New(opts)
+	require.NoError(t, err)
+	require.Equal(t, 6, m.EstimateCount())
+
+	var buff bytes.Buffer
+	err = m.ExecuteWithWriter(context.Background(), &buff)
+	require.NoError(t, err)
+
+	// alternatives already present in leftmost part are skipped just like payloads
+	results := strings.Split(strings.TrimSpace(buff.String()), "\n")
+	require.ElementsMatch(t, []string{
+		"api-dev.example.com", "api-stg.example.com", "api-prod.example.com",
+		"dev-stg.example.com", "dev-prod.example.com",
+	}, results)
+}
+
+func TestMutatorRanges(t *testing.T) {
+	t.Run("inline range", func(t *testing.T) {
+		opts := &Options{
+			Domains:  []string{"web.example.com"},
+			Patterns: []string{"{{sub}}{{range:01-10:3}}.{{suffix}}"},
+			Payloads: map[string][]string{"word": {"unused"}},
+			MaxSize:  math.MaxInt,
+		}
+		m, err := New(opts)
+		require.NoError(t, err)
+		require.Equal(t, 4, m.EstimateCount())
+
+		var buff bytes.Buffer
+		err = m.ExecuteWithWriter(context.Background(), &buff)
+		require.NoError(t, err)
+
+		results := strings.Split(strings.TrimSpace(buff.String()), "\n")
+		require.ElementsMatch(t, []string{"web01.example.com", "web04.example.com", "web07.example.com", "web10.example.com"}, results)
+	})
+
+	t.Run("configured range", func(t *testing.T) {
+		opts := &Options{
+			Domains:  []string{"web.example.com"},
+			Patterns: []string{"{{word}}-{{node}}.{{suffix}}"},
+			Payloads: map[string][]string{"word": {"db", "cache"}},
+			Ranges:   map[string]*Range{"node": {Start: 1, End: 99, Width: 2}},
+			MaxSize:  math.MaxInt,
+		}
+		m, err := New(opts)
+		require.NoError(t, err)
+		require.Equal(t, 198, m.EstimateCount())
+		require.Equal(t, 198, m.DryRun())
+	})
+
+	t.Run("invalid ranges", func(t *testing.T) {
+		_, err := New(&Options{
+			Domains:  []string{"web.example.com"},
+			Patterns: []string{"{{sub}}{{range:10-1}}.{{suffix}}"},
+			Payloads: map[string][]string{"word": {"db"}},
+		})
+		require.Error(t, err)
+
+		_, err = New(&Options{
+			Domains:  []string{"web.example.com"},
+			Patterns: []string{"{{sub}}{{word}}.{{suffix}}"},
+			Payloads: map[string][]string{"word": {"db"}},
+			Ranges:   map[string]*Range{"word": {Start: 1, End: 2}},
+		})
+		require.Error(t, err)
+	})
+}
+
+func TestMutatorAggregateVariables(t *testing.T) {
+	opts := &Options{
+		Domains:  []string{"a.b.c.example.com", "x.example.com"},
+		Patterns: []string{"{{sub_last}}-{{word}}.{{root}}", "{{word}}.{{suffix2}}"},
+		Payloads: map[string][]string{"word": {"dev"}},
+		MaxSize:  math.MaxInt,
+	}
+	m, err := New(opts)
+	require.NoError(t, err)
+
+	var buff bytes.Buffer
+	err = m.ExecuteWithWriter(context.Background(), &buff)
+	require.NoError(t, err)
+
+	results := strings.Split(strings.TrimSpace(buff.String()), "\n")
+	require.ElementsMatch(t, []string{"c-dev.example.com", "x-dev.example.com", "dev.example.com"}, results)
+}
+
+func TestMutatorPerLevel(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		opts := &Options{
+			Domains:  []string{"api.v1.internal.example.com"},
+			Patterns: []string{"{{sub}}-{{word}}.{{suffix}}"},
+			Payloads: map[string][]string{"word": {"dev"}},
+			MaxSize:  math.MaxInt,
+		}
+		m, err := New(opts)
+		require.NoError(t, err)
+		require.Equal(t, 1, m.EstimateCount())
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		opts := &Options{
+			Domains:  []string{"api.v1.internal.example.com"},
+			Patterns: []string{"{{sub}}-{{word}}.{{suffix}}"},
+			Payloads: map[string][]string{"word": {"dev"}},
+			PerLevel: true,
+			MaxSize:  math.MaxInt,
+		}
+		m, err := New(opts)
+		require.NoError(t, err)
+		require.Equal(t, 3, m.EstimateCount())
+
+		var buff bytes.Buffer
+		err = m.ExecuteWithWriter(context.Background(), &buff)
+		require.NoError(t, err)
+
+		results := strings.Split(strings.TrimSpace(buff.String()), "\n")
+		require.ElementsMatch(t, []string{
+			"api-dev.v1.internal.example.com",
+			"api.v1-dev.internal.example.com",
+			"api.v1.internal-dev.example.com",
+		}, results)
+	})
+}
+
+func TestMutatorLevelMutations(t *testing.T) {
+	opts := &Options{
+		Domains:        []string{"api.v1.example.com"},
+		Patterns:       []string{"{{sub}}.{{suffix}}"},
+		Payloads:       map[string][]string{"word": {"dev"}},
+		LevelMutations: []string{LevelInsert, LevelDelete, LevelPromote},
+		MaxSize:        math.MaxInt,
+	}
+	m, err := New(opts)
+	require.NoError(t, err)
+	require.Equal(t, 6, m.EstimateCount())
+
+	var buff bytes.Buffer
+	err = m.ExecuteWithWriter(context.Background(), &buff)
+	require.NoError(t, err)
+
+	results := strings.Split(strings.TrimSpace(buff.String()), "\n")
+	require.ElementsMatch(t, []string{
+		"api.v1.example.com",
+		"api.dev.v1.example.com",
+		"api.v1.dev.example.com",
+		"v1.example.com",
+		"api.example.com",
+		"v1.api.example.com",
+	}, results)
+
+	t.Run("unknown mutation", func(t *testing.T) {
+		_, err := New(&Options{Domains: []string{"api.example.com"}, LevelMutations: []string{"swap"}})
+		require.Error(t, err)
+	})
+}
+
+func TestMutatorAttackModes(t *testing.T) {
+	testcases := []struct {
+		name     string
+		mode     string
+		pattern  string
+		count    int
+		expected []string
+	}{
+		{name: "clusterbomb by default", pattern: "{{env}}-{{envshort}}.{{suffix}}", count: 4},
+		{name: "global pitchfork", mode: PitchforkMode, pattern: "{{env}}-{{envshort}}.{{suffix}}", count: 2, expected: []string{"production-prod.example.com", "staging-stg.example.com"}},
+		{name: "global sniper", mode: SniperMode, pattern: "{{env}}-{{envshort}}.{{suffix}}", count: 3},
+		{name: "pattern directive overrides global mode", mode: SniperMode, pattern: "pitchfork:{{env}}-{{envshort}}.{{suffix}}", count: 2, expected: []string{"production-prod.example.com", "staging-stg.example.com"}},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			m, err := New(&Options{
+				Domains:  []string{"api.example.com"},
+				Patterns: []string{tc.pattern},
+				Payloads: map[string][]string{
+					"env":      {"production", "staging"},
+					"envshort": {"prod", "stg"},
+				},
+				Mode:    tc.mode,
+				MaxSize: math.MaxInt,
+			})
+			require.NoError(t, err)
+			require.Equal(t, tc.count, m.EstimateCount())
+			results := collectResults(m)
+			require.Len(t, results, tc.count)
+			if tc.expected != nil {
+				require.ElementsMatch(t, tc.expected, results)
+			}
+		})
+	}
+
+	t.Run("unknown mode", func(t *testing.T) {
+		_, err := New(&Options{
+			Domains:  []string{"api.example.com"},
+			Patterns: []string{"{{env}}.{{suffix}}"},
+			Payloads: map[string][]string{"env": {"production", "staging"}},
+			Mode:     "battering-ram",
+		})
+		require.Error(t, err)
+	})
+}
+
+func TestMutatorTuples(t *testing.T) {
+	opts := &Options{
+		Domains:  []string{"api.example.com"},
+		Patterns: []string{"{{sub}}-{{env.abbr}}.{{env.region}}.{{suffix}}"},
+		Payloads: map[string][]string{"word": {"dev"}},
+		Tuples: map[string]Tuples{
+			"env": {
+				{"abbr": "prod", "region": "us-east-1"},
+				{"abbr": "stg", "region": "eu-west-1"},
+			},
+		},
+		MaxSize: math.MaxInt,
+	}
+	m, err := New(opts)
+	require.NoError(t, err)
+	require.Equal(t, 2, m.EstimateCount())
+
+	var buff bytes.Buffer
+	err = m.ExecuteWithWriter(context.Background(), &buff)
+	require.NoError(t, err)
+
+	results := strings.Split(strings.TrimSpace(buff.String()), "\n")
+	require.ElementsMatch(t, []string{"api-prod.us-east-1.example.com", "api-stg.eu-west-1.example.com"}, results)
+
+	t.Run("conflicting names", func(t *testing.T) {
+		_, err := New(&Options{
+			Domains:  []string{"api.example.com"},
+			Payloads: map[string][]string{"env": {"dev"}},
+			Tuples:   map[string]Tuples{"env": {{"abbr": "prod"}}},
+		})
+		require.Error(t, err)
+	})
+}
+
+func TestMutatorInstances(t *testing.T) {
+	testcases := []struct {
+		pattern  string
+		expected []string
+	}{
+		{pattern: "{{word#1}}.{{word#2}}.{{suffix}}", expected: []string{
+			"a.a.example.com", "a.b.example.com", "a.c.example.com",
+			"b.a.example.com", "b.b.example.com", "b.c.example.com",
+			"c.a.example.com", "c.b.example.com", "c.c.example.com",
+		}},
+		{pattern: "distinct:{{word#1}}.{{word#2}}.{{suffix}}", expected: []string{
+			"a.b.example.com", "a.c.example.com", "b.a.example.com",
+			"b.c.example.com", "c.a.example.com", "c.b.example.com",
+		}},
+		{pattern: "ordered:{{word#1}}.{{word#2}}.{{suffix}}", expected: []string{
+			"a.a.example.com", "a.b.example.com", "a.c.example.com",
+			"b.b.example.com", "b.c.example.com", "c.c.example.com",
+		}},
+		{pattern: "distinct,ordered:{{word#1}}.{{word#2}}.{{suffix}}", expected: []string{
+			"a.b.example.com", "a.c.example.com", "b.c.example.com",
+		}},
+		{pattern: "distinct,ordered:{{n#1}}-{{n#2}}.{{suffix}}", expected: []string{
+			"8-9.example.com", "8-10.example.com", "9-10.example.com",
+		}},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.pattern, func(t *testing.T) {
+			m, err := New(&Options{
+				Domains:  []string{"api.example.com"},
+				Patterns: []string{tc.pattern},
+				Payloads: map[string][]string{"word": {"a", "b", "c"}},
+				Ranges:   map[string]*Range{"n": {Start: 8, End: 10}},
+				MaxSize:  math.MaxInt,
+			})
+			require.NoError(t, err)
+			require.Equal(t, len(tc.expected), m.EstimateCount())
+
+			var buff bytes.Buffer
+			err = m.ExecuteWithWriter(context.Background(), &buff)
+			require.NoError(t, err)
+
+			results := strings.Split(strings.TrimSpace(buff.String()), "\n")
+			require.ElementsMatch(t, tc.expected, results)
+		})
+	}
+
+	t.Run("constraints outside clusterbomb", func(t *testing.T) {
+		for _, opts := range []*Options{
+			{Patterns: []string{"pitchfork,distinct:{{word#1}}.{{word#2}}.{{suffix}}"}},
+			{Patterns: []string{"ordered:{{word#1}}.{{word#2}}.{{suffix}}"}, Mode: SniperMode},
+		} {
+			opts.Domains = []string{"api.example.com"}
+			opts.Payloads = map[string][]string{"word": {"a", "b", "c"}}
+			_, err := New(opts)
+			require.Error(t, err, opts.Patterns[0])
+		}
+	})
+}
+
+func TestMutatorRules(t *testing.T) {
+	testcases := []struct {
+		name     string
+		rules    *Rules
+		expected []string
+	}{
+		{name: "without rules", expected: []string{
+			"dev-api.example.com", "test-api.example.com",
+			"api.api.example.com", "dev.api.example.com", "test.api.example.com",
+		}},
+		{name: "with rules", rules: &Rules{
+			Deny:            []string{`^test-`},
+			Forbidden:       []string{"dev."},
+			DistinctFromSub: true,
+		}, expected: []string{"dev-api.example.com", "test.api.example.com"}},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			m, err := New(&Options{
+				Domains:  []string{"api.example.com"},
+				Patterns: []string{"{{word}}-{{sub}}.{{suffix}}", "{{word}}.{{sub}}.{{suffix}}"},
+				Payloads: map[string][]string{"word": {"api", "dev", "test"}},
+				Rules:    tc.rules,
+				MaxSize:  math.MaxInt,
+			})
+			require.NoError(t, err)
+			require.ElementsMatch(t, tc.expected, collectResults(m))
+		})
+	}
+
+	t.Run("invalid rules", func(t *testing.T) {
+		_, err := New(&Options{
+			Domains:  []string{"api.example.com"},
+			Patterns: []string{"{{word}}-{{sub}}.{{suffix}}"},
+			Payloads: map[string][]string{"word": {"api"}},
+			Rules:    &Rules{Deny: []string{"("}},
+		})
+		require.Error(t, err)
+	})
+}
+
+func TestMutatorValidation(t *testing.T) {
+	testcases := []struct {
+		name       string
+		validation string
+		expected   []string
+		rejections map[string]int
+		rejected   int
+	}{
+		{name: "strict by default", expected: []string{
+			"api-dev.example.com", "dev.api.example.com", "api--qa.example.com",
+		}, rejections: map[string]int{RejectHyphen: 1, RejectInvalidChar: 2}, rejected: 3},
+		{name: "lenient", validation: ValidationLenient, expected: []string{
+			"api-dev.example.com", "dev.api.example.com", "api--qa.example.com",
+			"qa.api.example.com", "api-a_b.example.com", "a_b.api.example.com",
+		}, rejections: map[string]int{}},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			m, err := New(&Options{
+				Domains:    []string{"api.example.com"},
+				Patterns:   []string{"{{sub}}-{{word}}.{{suffix}}", "{{word}}.{{sub}}.{{suffix}}"},
+				Payloads:   map[string][]string{"word": {"dev", "-qa", "a_b"}},
+				Validation: tc.validation,
+				MaxSize:    math.MaxInt,
+			})
+			require.NoError(t, err)
+			require.ElementsMatch(t, tc.expected, collectResults(m))
+			require.Equal(t, tc.rejections, m.rejections)
+			require.Equal(t, tc.rejected, m.RejectedCount())
+		})
+	}
+
+	t.Run("unknown mode", func(t *testing.T) {
+		_, err := New(&Options{
+			Domains:    []string{"api.example.com"},
+			Patterns:   []string{"{{sub}}-{{word}}.{{suffix}}"},
+			Payloads:   map[string][]string{"word": {"dev"}},
+			Validation: "relaxed",
+		})
+		require.Error(t, err)
+	})
+}
+
+func TestMutatorIDN(t *testing.T) {
+	testcases := []struct {
+		name     string
+		form     string
+		expected []string
+	}{
+		{name: "ascii by default", expected: []string{"shop-dev.xn--mnchen-3ya.de", "xn--shop-bro-c6a.xn--mnchen-3ya.de"}},
+		{name: "unicode", form: IDNUnicode, expected: []string{"shop-dev.münchen.de", "shop-büro.münchen.de"}},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			m, err := New(&Options{
+				Domains:   []string{"shop.xn--mnchen-3ya.de"},
+				Patterns:  []string{"{{sub}}-{{word}}.{{suffix}}"},
+				Payloads:  map[string][]string{"word": {"dev", "büro"}},
+				IDNOutput: tc.form,
+				MaxSize:   math.MaxInt,
+			})
+			require.NoError(t, err)
+			require.ElementsMatch(t, tc.expected, collectResults(m))
+		})
+	}
+
+	t.Run("unknown form", func(t *testing.T) {
+		_, err := New(&Options{
+			Domains:   []string{"shop.xn--mnchen-3ya.de"},
+			Patterns:  []string{"{{sub}}-{{word}}.{{suffix}}"},
+			Payloads:  map[string][]string{"word": {"dev"}},
+			IDNOutput: "utf8",
+		})
+		require.Error(t, err)
+	})
+}
+
+func TestMutatorDomainChan(t *testing.T) {
+	for _, dedupe := range []bool{false, true} {
+		t.Run(fmt.Sprintf("dedupe %v", dedupe), func(t *testing.T) {
+			domains := make(chan string)
+			m, err := New(&Options{
+				Domains:       []string{"www.example.com"},
+				DomainChan:    domains,
+				Patterns:      []string{"{{sub}}-{{word}}.{{suffix}}"},
+				Payloads:      map[string][]string{"word": {"dev"}},
+				DisableDedupe: !dedupe,
+				MaxSize:       math.MaxInt,
+			})
+			require.NoError(t, err)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			results := m.Execute(ctx)
+
+			// permutations are emitted while channel is still open
+			require.Equal(t, "www-dev.example.com", <-results)
+			domains <- "api.example.com"
+			require.Equal(t, "api-dev.example.com", <-results)
+			domains <- "invalid..*.example.com"
+			// duplicate inputs are skipped even without dedupe
+			domains <- "https://API.example.com./"
+			domains <- "cdn.example.com"
+			require.Equal(t, "cdn-dev.example.com", <-results)
+			close(domains)
+
+			_, ok := <-results
+			require.False(t, ok)
+			require.Equal(t, 1, m.CollapsedCount())
+		})
+	}
+
+	t.Run("only channel", func(t *testing.T) {
+		domains := make(chan string, 2)
+		domains <- "api.example.com"
+		close(domains)
+		m, err := New(&Options{
+			DomainChan: domains,
+			Patterns:   []string{"{{sub}}-{{word}}.{{suffix}}"},
+			Payloads:   map[string][]string{"word": {"dev"}},
+			MaxSize:    math.MaxInt,
+		})
+		require.NoError(t, err)
+
+		var buff bytes.Buffer
+		require.NoError(t, m.ExecuteWithWriter(context.Background(), &buff))
+		require.Equal(t, "api-dev.example.com\n", buff.String())
+	})
+
+	t.Run("limit stops reading channel", func(t *testing.T) {
+		// channel is never closed so execution only ends if it is cancelled at limit
+		domains := make(chan string)
+		m, err := New(&Options{
+			Domains:    []string{"www.example.com"},
+			DomainChan: domains,
+			Patterns:   []string{"{{sub}}-{{word}}.{{suffix}}"},
+			Payloads:   map[string][]string{"word": {"dev"}},
+			Limit:      1,
+			MaxSize:    math.MaxInt,
+		})
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		var buff bytes.Buffer
+		require.NoError(t, m.ExecuteWithWriter(ctx, &buff))
+		require.Equal(t, "www-dev.example.com\n", buff.String())
+	})
+
+	t.Run("limit with rejected results", func(t *testing.T) {
+		// generation keeps counting rejections after limit until it stops
+		// so results must not be closed before it does (run with -race)
+		domains := make(chan string)
+		m, err := New(&Options{
+			Domains:    []string{"www.example.com", "api.example.com", "cdn.example.com"},
+			DomainChan: domains,
+			Patterns:   []string{"{{sub}}-{{word}}.{{suffix}}", "{{word}}-{{sub}}.{{suffix}}", "{{sub}}-{{word}}.{{suffix}}"},
+			Payloads:   map[string][]string{"word": {"dev", "a_b", "qa", "c_d", "stage", "e_f"}},
+			Limit:      1,
+			MaxSize:    math.MaxInt,
+		})
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		var buff bytes.Buffer
+		require.NoError(t, m.ExecuteWithWriter(ctx, &buff))
+		require.Equal(t, "www-dev.example.com\n", buff.String())
+		require.Greater(t, m.RejectedCount(), 0)
+	})
+}
+
+func TestMutatorInputFormats(t *testing.T) {
+	t.Run("jsonl", func(t *testing.T) {
+		m, err := New(&Options{
+			Domains: []string{
+				`{"host":"api.example.com","source":"crtsh"}`,
+				`{"host":"dev.example.com","source":"dnsdumpster"}`,
+			},
+			InputFormat: InputFormatJSONL,
+			Patterns:    []string{"{{sub}}-{{source}}.{{suffix}}"},
+			Payloads:    map[string][]string{"word": {"test"}},
+		})
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{"api-crtsh.example.com", "dev-dnsdumpster.example.com"}, collectResults(m))
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		domains := make(chan string, 3)
+		domains <- "env,name"
+		domains <- "prod,api.example.com"
+		domains <- "invalid"
+		close(domains)
+		m, err := New(&Options{
+			DomainChan:  domains,
+			InputFormat: InputFormatCSV,
+			InputColumn: "name",
+			Patterns:    []string{"{{sub}}.{{env}}.{{suffix}}"},
+			Payloads:    map[string][]string{"word": {"test"}},
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{"api.prod.example.com"}, collectResults(m))
+	})
+
+	t.Run("invalid format", func(t *testing.T) {
+		_, err := New(&Options{Domains: []string{"api.example.com"}, InputFormat: "xml"})
+		require.Error(t, err)
+	})
+}
+
+func TestMutatorAnnotations(t *testing.T) {
+	m, err := New(&Options{
+		Domains:  []string{"api.example.com env=prod team=payments", "dev.example.com env=staging", "www.example.com"},
+		Patterns: []string{"{{sub}}-{{env}}.{{suffix}}", "{{team}}.{{sub}}.{{suffix}}"},
+		Payloads: map[string][]string{"word": {"test"}},
+	})
+	require.NoError(t, err)
+	// patterns are skipped for inputs without annotations
+	require.ElementsMatch(t, []string{"api-prod.example.com", "payments.api.example.com", "dev-staging.example.com"}, collectResults(m))
+	require.Equal(t, 3, m.EstimateCount())
+
+	t.Run("annotation vars", func(t *testing.T) {
+		m, err := New(&Options{
+			Domains:  []string{"api.example.com env=prod"},
+			Patterns: []string{"{{sub}}-{{env}}.{{suffix}}"},
+			Payloads: map[string][]string{"word": {"test"}},
+			Ranges:   map[string]*Range{"node": {Start: 1, End: 2}},
+			Tuples:   map[string]Tuples{"svc": {{"abbr": "prod"}}},
+		})
+		require.NoError(t, err)
+		require.True(t, m.isAnnotationVars([]string{"env"}))
+		// variables no input has are missing payloads rather than annotations
+		require.False(t, m.isAnnotationVars([]string{"region"}))
+		require.False(t, m.isAnnotationVars([]string{"env", "region"}))
+		require.False(t, m.isAnnotationVars([]string{"env", "sub1"}))
+		require.False(t, m.isAnnotationVars([]string{"word"}))
+		require.False(t, m.isAnnotationVars([]string{"node"}))
+		require.False(t, m.isAnnotationVars([]string{"svc"}))
+		require.False(t, m.isAnnotationVars(nil))
+	})
+}
+
+func TestMutatorInputNormalization(t *testing.T) {
+	m, err := New(&Options{
+		Domains: []string{
+			"# passive sources",
+			"api.example.com env=prod",
+			"API.Example.com. team=payments # duplicate",
+			"api.example.com:443",
+			"https://api.example.com/x",
+			"dev.example.com",
+		},
+		Patterns: []string{"{{sub}}-{{env}}.{{suffix}}", "{{team}}.{{sub}}.{{suffix}}"},
+		Payloads: map[string][]string{"word": {"test"}},
+	})
+	require.NoError(t, err)
+	require.Len(t, m.Inputs, 2)
+	require.Equal(t, 3, m.CollapsedCount())
+	// annotations of duplicates are merged
+	require.ElementsMatch(t, []string{"api-prod.example.com", "payments.api.example.com"}, collectResults(m))
+}
+
+func TestMutatorTemplateInputs(t *testing.T) {
+	t.Run("default payload", func(t *testing.T) {
+		m, err := New(&Options{
+			Domains:  []string{"prod.*.hackerone.com", "*.*-*.example.com", "api.example.com"},
+			Patterns: []string{"{{word}}.{{sub}}.{{suffix}}"},
+			Payloads: map[string][]string{"word": {"api", "dev"}},
+		})
+		require.NoError(t, err)
+		expected := []string{
+			"prod.api.hackerone.com", "prod.dev.hackerone.com",
+			"api-api.example.com", "api-dev.example.com", "dev-api.example.com", "dev-dev.example.com",
+			// patterns are only applied to regular inputs
+			"api.api.example.com", "dev.api.example.com",
+		}
+		require.ElementsMatch(t, expected, collectResults(m))
+		require.Equal(t, len(expected), m.EstimateCount())
+	})
+
+	t.Run("custom payload", func(t *testing.T) {
+		m, err := New(&Options{
+			Domains:         []string{"prod.*.hackerone.com"},
+			Patterns:        []string{"{{word}}.{{sub}}.{{suffix}}"},
+			Payloads:        map[string][]string{"word": {"api", "dev"}, "env": {"stg"}},
+			WildcardPayload: "env",
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{"prod.stg.hackerone.com"}, collectResults(m))
+	})
+
+	t.Run("missing payload", func(t *testing.T) {
+		_, err := New(&Options{
+			Domains:         []string{"prod.*.hackerone.com"},
+			Patterns:        []string{"{{word}}.{{sub}}.{{suffix}}"},
+			Payloads:        map[string][]string{"word": {"api", "dev"}},
+			WildcardPayload: "missing",
+		})
+		require.Error(t, err)
+	})
+
+	t.Run("empty default payload", func(t *testing.T) {
+		_, err := New(&Options{
+			Domains:  []string{"prod.*.hackerone.com"},
+			Patterns: []string{"{{env}}.{{sub}}.{{suffix}}"},
+			Payloads: map[string][]string{"env": {"stg"}},
+		})
+		require.Error(t, err)
+
+		// payload is only required by template inputs
+		m, err := New(&Options{
+			Domains:  []string{"api.hackerone.com"},
+			Patterns: []string{"{{env}}.{{sub}}.{{suffix}}"},
+			Payloads: map[string][]string{"env": {"stg"}},
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{"stg.api.hackerone.com"}, collectResults(m))
+	})
+}
+
+func TestMutatorTokens(t *testing.T) {
+	m, err := New(&Options{
+		Domains: []string{"api-gateway-eu2.example.com", "www.example.com"},
+		Patterns: []string{
+			// swap first two tokens
+			"{{subtok2}}{{subtok_sep}}{{subtok1}}{{subtok_sep}}{{subtok3}}{{subtok4}}.{{suffix}}",
+			// increment region number
+			"{{subtok1}}{{subtok_sep}}{{subtok2}}{{subtok_sep}}{{subtok3}}{{number}}.{{suffix}}",
+		},
+		Payloads:  map[string][]string{"number": {"3"}},
+		Tokenizer: &Tokenizer{Separators: "-"},
+	})
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"gateway-api-eu2.example.com", "api-gateway-eu3.example.com"}, collectResults(m))
+}
+
+func TestMutatorTokenSwap(t *testing.T) {
+	opts := &Options{
+		Domains:   []string{"api-dev.example.com"},
+		Patterns:  []string{"{{sub}}.{{suffix}}"},
+		Payloads:  map[string][]string{"word": {"test"}},
+		TokenSwap: true,
+		Classes:   map[string][]string{"env": {"dev", "qa", "prod"}, "service": {"api", "web"}},
+	}
+	m, err := New(opts)
+	require.NoError(t, err)
+	expected := []string{"api-dev.example.com", "api-qa.example.com", "api-prod.example.com", "web-dev.example.com"}
+	require.ElementsMatch(t, expected, collectResults(m))
+	require.Equal(t, len(expected), m.EstimateCount())
+
+	t.Run("default classes", func(t *testing.T) {
+		m, err := New(&Options{
+			Domains:   []string{"api-dev.example.com"},
+			Patterns:  []string{"{{sub}}.{{suffix}}"},
+			Payloads:  map[string][]string{"word": {"test"}},
+			TokenSwap: true,
+		})
+		require.NoError(t, err)
+		require.Contains(t, collectResults(m), "api-staging.example.com")
+	})
+}
+
+func TestMutatorSeries(t *testing.T) {
+	m, err := New(&Options{
+		Domains:      []string{"web01.example.com", "web02.example.com", "web04.example.com"},
+		Patterns:     []string{"{{sub}}.{{suffix}}"},
+		Payloads:     map[string][]string{"word": {"test"}},
+		SeriesWindow: 2,
+		MaxSize:      math.MaxInt,
+	})
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{
+		"web01.example.com", "web02.example.com", "web04.example.com",
+		"web03.example.com", "web05.example.com", "web06.example.com",
+	}, collectResults(m))
+
+	t.Run("enrich", func(t *testing.T) {
+		m, err := New(&Options{
+			Domains:  []string{"node-3.example.com"},
+			Patterns: []string{"{{sub}}.{{suffix}}"},
+			Payloads: map[string][]string{"word": {"test"}, "number": {"1"}},
+			Enrich:   true,
+		})
+		require.NoError(t, err)
+		require.Equal(t, DefaultSeriesWindow, m.Options.SeriesWindow)
+		// numbers are extrapolated instead of being added to payloads
+		require.Equal(t, []string{"1"}, m.Options.Payloads["number"])
+		require.Contains(t, collectResults(m), "node-8.example.com")
+	})
+}
+
 // Helper functions
 
+func collectResults(m *Mutator) []string {
+	var results []string
+	for v := range m.Execute(context.Background()) {
+		results = append(results, v)
+	}
+	return results
+}
+
 func generateLargePayload(size int) []string {
 	payload := make([]string, size)
 	for i := 0; i < size; i++ {
@@ -369,10 +1314,9 @@ func generateLargePayload(size int) []string {
 
 func BenchmarkMutatorNew(b *testing.B) {
 	opts := &Options{
-		Domains:       []string{"api.example.com", "dev.example.com", "prod.example.com"},
-		Patterns:      testConfig.Patterns,
-		Payloads:      testConfig.Payloads,
-		DedupeResults: true,
+		Domains:  []string{"api.example.com", "dev.example.com", "prod.example.com"},
+		Patterns: testConfig.Patterns,
+		Payloads: testConfig.Payloads,
 	}
 
 	b.ResetTimer()
@@ -383,11 +1327,10 @@ func BenchmarkMutatorNew(b *testing.B) {
 
 func BenchmarkMutatorExecute(b *testing.B) {
 	opts := &Options{
-		Domains:       []string{"example.com"},
-		Patterns:      testConfig.Patterns,
-		Payloads:      testConfig.Payloads,
-		DedupeResults: true,
-		MaxSize:       math.MaxInt,
+		Domains:  []string{"example.com"},
+		Patterns: testConfig.Patterns,
+		Payloads: testConfig.Payloads,
+		MaxSize:  math.MaxInt,
 	}
 	m, _ := New(opts)
 
@@ -402,10 +1345,9 @@ func BenchmarkMutatorExecute(b *testing.B) {
 
 func BenchmarkMutatorEstimateCount(b *testing.B) {
 	opts := &Options{
-		Domains:       []string{"example.com"},
-		Patterns:      testConfig.Patterns,
-		Payloads:      testConfig.Payloads,
-		DedupeResults: true,
+		Domains:  []string{"example.com"},
+		Patterns: testConfig.Patterns,
+		Payloads: testConfig.Payloads,
 	}
 	m, _ := New(opts)
 
@@ -0,0 +1,44 @@
+package alterx
+
+import (
+	"net"
+	"strings"
+)
+
+// inputCommentPrefix is prefix of comments in input lines
+const inputCommentPrefix = "#"
+
+// NormalizeDomain converts raw input (domain, host:port or url) to its canonical hostname
+// by stripping whitespace, scheme, credentials, port, path and trailing dots and lowercasing it
+// ex: ` https://API.Example.com.:443/x?y=1 ` => `api.example.com`
+func NormalizeDomain(raw string) string {
+	value := strings.TrimSpace(raw)
+	if i := strings.Index(value, "://"); i >= 0 {
+		value = value[i+len("://"):]
+	}
+	if i := strings.IndexAny(value, "/?#"); i >= 0 {
+		value = value[:i]
+	}
+	if i := strings.LastIndex(value, "@"); i >= 0 {
+		value = value[i+1:]
+	}
+	if host, _, err := net.SplitHostPort(value); err == nil {
+		value = host
+	}
+	return strings.ToLower(strings.TrimRight(value, "."))
+}
+
+// stripComment removes comment from input line (ex: `api.example.com # prod` => `api.example.com`)
+// comments must start at beginning of line or be preceded by whitespace
+func stripComment(line string) string {
+	if strings.HasPrefix(line, inputCommentPrefix) {
+		return ""
+	}
+	if i := strings.Index(line, " "+inputCommentPrefix); i >= 0 {
+		line = line[:i]
+	}
+	if i := strings.Index(line, "\t"+inputCommentPrefix); i >= 0 {
+		line = line[:i]
+	}
+	return line
+}
@@ -0,0 +1,250 @@
+package alterx
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/projectdiscovery/fasttemplate"
+	sliceutil "github.com/projectdiscovery/utils/slice"
+)
+
+const (
+	// OptionalOpen marker - begin of an optional segment (ex: [{{word}}-]{{sub}}.{{suffix}})
+	OptionalOpen = "["
+	// OptionalClose marker - end of an optional segment
+	OptionalClose = "]"
+	// AlternationOpen marker - begin of inline alternation (ex: {{sub}}-(dev|stg|prod).{{suffix}})
+	AlternationOpen = "("
+	// AlternationClose marker - end of inline alternation
+	AlternationClose = ")"
+	// AlternationSeparator separates choices of inline alternation
+	AlternationSeparator = "|"
+	// alternationVarPrefix is prefix of variables generated for inline alternations
+	alternationVarPrefix = "_alt"
+	// DirectiveSeparator separates leading directives from pattern (ex: pitchfork:{{sub}}-{{env}}.{{suffix}})
+	DirectiveSeparator = ":"
+	// DirectiveListSeparator separates multiple leading directives of a pattern
+	DirectiveListSeparator = ","
+)
+
+// directiveRegex matches leading directives of a pattern. colon is never part of a
+// valid hostname so it can't be confused with literal text of pattern
+var directiveRegex = regexp.MustCompile(`^([a-z]+(?:` + DirectiveListSeparator + `[a-z]+)*)` + DirectiveSeparator)
+
+// pattern is a compiled representation of a user supplied pattern
+type pattern struct {
+	// raw is the pattern as provided by user
+	raw string
+	// template is statement with all pattern syntax resolved to plain placeholders
+	template string
+	// optional is true when template includes at least one optional segment
+	// such templates are silently skipped if any of their variables are missing
+	optional bool
+	// payloads contains payloads local to this pattern (ex: inline alternations and ranges)
+	payloads map[string]Generator
+	// mode is attack mode of this pattern set using directive (empty = global mode)
+	mode string
+	// distinct and ordered are constraints on instances of repeated variables (ex: {{word#1}})
+	distinct bool
+	ordered  bool
+}
+
+// compilePatterns validates given patterns and expands them into templates
+func compilePatterns(patterns []string) ([]*pattern, error) {
+	var compiled []*pattern
+	for _, raw := range patterns {
+		body, directives := parseDirectives(raw)
+		variants, err := expandOptional(body)
+		if err != nil {
+			return nil, fmt.Errorf("pattern '%s': %w", raw, err)
+		}
+		for _, v := range variants {
+			if err := v.applyDirectives(directives); err != nil {
+				return nil, fmt.Errorf("pattern '%s': %w", raw, err)
+			}
+			if v.template, v.payloads, err = extractAlternations(v.template); err != nil {
+				return nil, fmt.Errorf("pattern '%s': %w", raw, err)
+			}
+			var ranges map[string]Generator
+			if v.template, ranges, err = extractRanges(v.template); err != nil {
+				return nil, fmt.Errorf("pattern '%s': %w", raw, err)
+			}
+			for k, r := range ranges {
+				if v.payloads == nil {
+					v.payloads = map[string]Generator{}
+				}
+				v.payloads[k] = r
+			}
+			// check if all placeholders are correctly used and are valid
+			if _, err := fasttemplate.NewTemplate(v.template, ParenthesisOpen, ParenthesisClose); err != nil {
+				return nil, err
+			}
+			// check if all filters used in placeholders exist and have valid arguments
+			if err := validateFilters(v.template); err != nil {
+				return nil, fmt.Errorf("pattern '%s': %w", raw, err)
+			}
+			v.raw = raw
+			compiled = append(compiled, v)
+		}
+	}
+	return compiled, nil
+}
+
+// parseDirectives splits leading directives from pattern
+// ex: `pitchfork:{{sub}}-{{env}}.{{suffix}}` => `{{sub}}-{{env}}.{{suffix}}`, [pitchfork]
+func parseDirectives(raw string) (string, []string) {
+	match := directiveRegex.FindStringSubmatch(raw)
+	if match == nil {
+		return raw, nil
+	}
+	return raw[len(match[0]):], strings.Split(match[1], DirectiveListSeparator)
+}
+
+// applyDirectives configures pattern using given directives
+func (p *pattern) applyDirectives(directives []string) error {
+	for _, v := range directives {
+		switch v {
+		case DistinctDirective:
+			p.distinct = true
+			continue
+		case OrderedDirective:
+			p.ordered = true
+			continue
+		}
+		if validateAttackMode(v) == nil {
+			if p.mode != "" && p.mode != v {
+				return fmt.Errorf("conflicting attack modes `%v` and `%v`", p.mode, v)
+			}
+			p.mode = v
+			continue
+		}
+		return fmt.Errorf("unknown directive `%v`", v)
+	}
+	return nil
+}
+
+// expandOptional expands all optional segments of a pattern into variants with and without
+// that segment. ex: `[{{word}}-]{{sub}}.{{suffix}}` => `{{word}}-{{sub}}.{{suffix}}`, `{{sub}}.{{suffix}}`
+// duplicate variants (ex: `[a][a]`) are only returned once
+func expandOptional(data string) ([]*pattern, error) {
+	start, end, err := findOptionalSegment(data)
+	if err != nil {
+		return nil, err
+	}
+	if start < 0 {
+		return []*pattern{{template: data}}, nil
+	}
+	prefix, inner, suffix := data[:start], data[start+len(OptionalOpen):end], data[end+len(OptionalClose):]
+
+	with, err := expandOptional(prefix + inner + suffix)
+	if err != nil {
+		return nil, err
+	}
+	without, err := expandOptional(prefix + suffix)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range with {
+		v.optional = true
+	}
+
+	// purge duplicates while preferring non-optional variants
+	var variants []*pattern
+	seen := map[string]*pattern{}
+	for _, v := range append(with, without...) {
+		if existing, ok := seen[v.template]; ok {
+			existing.optional = existing.optional && v.optional
+			continue
+		}
+		seen[v.template] = v
+		variants = append(variants, v)
+	}
+	return variants, nil
+}
+
+// findOptionalSegment returns position of the first top-level optional segment markers
+// markers inside placeholders are ignored. -1 is returned if there is no optional segment
+func findOptionalSegment(data string) (int, int, error) {
+	start, depth := -1, 0
+	for i := 0; i < len(data); {
+		switch {
+		case strings.HasPrefix(data[i:], ParenthesisOpen):
+			end := strings.Index(data[i:], ParenthesisClose)
+			if end < 0 {
+				// unterminated placeholders are reported by template validation
+				i = len(data)
+				continue
+			}
+			i += end + len(ParenthesisClose)
+			continue
+		case strings.HasPrefix(data[i:], OptionalOpen):
+			if depth == 0 {
+				start = i
+			}
+			depth++
+		case strings.HasPrefix(data[i:], OptionalClose):
+			if depth == 0 {
+				return -1, -1, fmt.Errorf("unexpected `%v` at position %v", OptionalClose, i)
+			}
+			depth--
+			if depth == 0 {
+				return start, i, nil
+			}
+		}
+		i++
+	}
+	if depth > 0 {
+		return -1, -1, fmt.Errorf("optional segment starting at position %v is not closed", start)
+	}
+	return -1, -1, nil
+}
+
+// extractAlternations replaces all inline alternations in template with variables
+// and returns them as payloads. ex: `{{sub}}-(dev|prod).{{suffix}}` => `{{sub}}-{{_alt1}}.{{suffix}}`
+// with payload `_alt1` = [dev, prod]
+func extractAlternations(template string) (string, map[string]Generator, error) {
+	var sb strings.Builder
+	payloads := map[string]Generator{}
+	for i := 0; i < len(template); {
+		switch {
+		case strings.HasPrefix(template[i:], ParenthesisOpen):
+			end := strings.Index(template[i:], ParenthesisClose)
+			if end < 0 {
+				// unterminated placeholders are reported by template validation
+				sb.WriteString(template[i:])
+				i = len(template)
+				continue
+			}
+			sb.WriteString(template[i : i+end+len(ParenthesisClose)])
+			i += end + len(ParenthesisClose)
+		case strings.HasPrefix(template[i:], AlternationOpen):
+			end := strings.Index(template[i:], AlternationClose)
+			if end < 0 {
+				return "", nil, fmt.Errorf("alternation starting at position %v is not closed", i)
+			}
+			group := template[i+len(AlternationOpen) : i+end]
+			if strings.Contains(group, AlternationOpen) || strings.Contains(group, ParenthesisOpen) {
+				return "", nil, fmt.Errorf("alternation `%v` cannot contain nested groups or placeholders", group)
+			}
+			choices := strings.Split(group, AlternationSeparator)
+			if sliceutil.Contains(choices, "") {
+				return "", nil, fmt.Errorf("alternation `%v` contains empty choice, use optional segment instead", group)
+			}
+			name := alternationVarPrefix + strconv.Itoa(len(payloads)+1)
+			payloads[name] = Wordlist(sliceutil.Dedupe(choices))
+			sb.WriteString(ParenthesisOpen + name + ParenthesisClose)
+			i += end + len(AlternationClose)
+		case strings.HasPrefix(template[i:], AlternationClose):
+			return "", nil, fmt.Errorf("unexpected `%v` at position %v", AlternationClose, i)
+		default:
+			sb.WriteByte(template[i])
+			i++
+		}
+	}
+	if len(payloads) == 0 {
+		return template, nil, nil
+	}
+	return sb.String(), payloads, nil
+}
@@ -0,0 +1,155 @@
+package alterx
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func getTemplates(patterns []*pattern) []string {
+	var templates []string
+	for _, v := range patterns {
+		templates = append(templates, v.template)
+	}
+	return templates
+}
+
+func TestExpandOptional(t *testing.T) {
+	testcases := []struct {
+		pattern  string
+		expected []string
+	}{
+		{pattern: "{{sub}}.{{suffix}}", expected: []string{"{{sub}}.{{suffix}}"}},
+		{pattern: "[{{word}}-]{{sub}}.{{suffix}}", expected: []string{"{{word}}-{{sub}}.{{suffix}}", "{{sub}}.{{suffix}}"}},
+		{pattern: "[{{word}}-]{{sub}}[-{{number}}].{{suffix}}", expected: []string{
+			"{{word}}-{{sub}}-{{number}}.{{suffix}}",
+			"{{word}}-{{sub}}.{{suffix}}",
+			"{{sub}}-{{number}}.{{suffix}}",
+			"{{sub}}.{{suffix}}",
+		}},
+		// nested optional segments
+		{pattern: "[{{word}}[-{{number}}]-]{{sub}}.{{suffix}}", expected: []string{
+			"{{word}}-{{number}}-{{sub}}.{{suffix}}",
+			"{{word}}-{{sub}}.{{suffix}}",
+			"{{sub}}.{{suffix}}",
+		}},
+		// duplicate variants are purged
+		{pattern: "{{sub}}[-dev][-dev].{{suffix}}", expected: []string{
+			"{{sub}}-dev-dev.{{suffix}}",
+			"{{sub}}-dev.{{suffix}}",
+			"{{sub}}.{{suffix}}",
+		}},
+	}
+	for _, tc := range testcases {
+		got, err := expandOptional(tc.pattern)
+		require.NoError(t, err, tc.pattern)
+		require.Equal(t, tc.expected, getTemplates(got), tc.pattern)
+	}
+}
+
+func TestExpandOptionalFlags(t *testing.T) {
+	got, err := expandOptional("{{sub}}[-dev][-dev].{{suffix}}")
+	require.NoError(t, err)
+	for _, v := range got {
+		require.Equal(t, v.template != "{{sub}}.{{suffix}}", v.optional, v.template)
+	}
+}
+
+func TestExpandOptionalErrors(t *testing.T) {
+	testcases := []string{
+		"[{{word}}-{{sub}}.{{suffix}}",
+		"{{word}}-]{{sub}}.{{suffix}}",
+		"[[{{word}}]-{{sub}}.{{suffix}}",
+	}
+	for _, v := range testcases {
+		_, err := expandOptional(v)
+		require.Error(t, err, v)
+	}
+}
+
+func TestCompilePatterns(t *testing.T) {
+	t.Run("raw pattern is preserved", func(t *testing.T) {
+		got, err := compilePatterns([]string{"[{{word}}-]{{sub}}.{{suffix}}", "{{sub}}.{{suffix}}"})
+		require.NoError(t, err)
+		require.Len(t, got, 3)
+		require.Equal(t, "[{{word}}-]{{sub}}.{{suffix}}", got[0].raw)
+		require.Equal(t, "[{{word}}-]{{sub}}.{{suffix}}", got[1].raw)
+		require.Equal(t, "{{sub}}.{{suffix}}", got[2].raw)
+	})
+
+	t.Run("invalid filter in optional segment", func(t *testing.T) {
+		_, err := compilePatterns([]string{"[{{word|unknown}}-]{{sub}}.{{suffix}}"})
+		require.Error(t, err)
+	})
+}
+
+func TestExtractAlternations(t *testing.T) {
+	t.Run("single alternation", func(t *testing.T) {
+		template, payloads, err := extractAlternations("{{sub}}-(dev|stg|prod).{{suffix}}")
+		require.NoError(t, err)
+		require.Equal(t, "{{sub}}-{{_alt1}}.{{suffix}}", template)
+		require.Equal(t, map[string]Generator{"_alt1": Wordlist{"dev", "stg", "prod"}}, payloads)
+	})
+
+	t.Run("multiple alternations", func(t *testing.T) {
+		template, payloads, err := extractAlternations("(api|www)-{{sub}}-(dev|dev|qa).{{suffix}}")
+		require.NoError(t, err)
+		require.Equal(t, "{{_alt1}}-{{sub}}-{{_alt2}}.{{suffix}}", template)
+		require.Equal(t, Wordlist{"api", "www"}, payloads["_alt1"])
+		require.Equal(t, Wordlist{"dev", "qa"}, payloads["_alt2"])
+	})
+
+	t.Run("pipes inside placeholders are ignored", func(t *testing.T) {
+		template, payloads, err := extractAlternations("{{sub|upper}}.{{suffix}}")
+		require.NoError(t, err)
+		require.Equal(t, "{{sub|upper}}.{{suffix}}", template)
+		require.Nil(t, payloads)
+	})
+
+	t.Run("invalid alternations", func(t *testing.T) {
+		for _, v := range []string{"{{sub}}-(dev|prod.{{suffix}}", "{{sub}}-dev).{{suffix}}", "{{sub}}-(|dev).{{suffix}}", "({{word}}|dev).{{suffix}}"} {
+			_, _, err := extractAlternations(v)
+			require.Error(t, err, v)
+		}
+	})
+}
+
+func TestCompilePatternsWithAlternations(t *testing.T) {
+	got, err := compilePatterns([]string{"{{sub}}[-(dev|prod)].{{suffix}}"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"{{sub}}-{{_alt1}}.{{suffix}}", "{{sub}}.{{suffix}}"}, getTemplates(got))
+	require.Equal(t, Wordlist{"dev", "prod"}, got[0].payloads["_alt1"])
+	require.Nil(t, got[1].payloads)
+}
+
+func TestCompilePatternsWithDirectives(t *testing.T) {
+	t.Run("attack mode", func(t *testing.T) {
+		got, err := compilePatterns([]string{"pitchfork:{{sub}}-{{env}}[-{{envshort}}].{{suffix}}", "{{sub}}.{{suffix}}"})
+		require.NoError(t, err)
+		require.Equal(t, []string{"{{sub}}-{{env}}-{{envshort}}.{{suffix}}", "{{sub}}-{{env}}.{{suffix}}", "{{sub}}.{{suffix}}"}, getTemplates(got))
+		require.Equal(t, PitchforkMode, got[0].mode)
+		require.Equal(t, PitchforkMode, got[1].mode)
+		require.Empty(t, got[2].mode)
+	})
+
+	t.Run("instance constraints", func(t *testing.T) {
+		got, err := compilePatterns([]string{"ordered,distinct,sniper:{{word#1}}.{{word#2}}.{{suffix}}"})
+		require.NoError(t, err)
+		require.True(t, got[0].distinct)
+		require.True(t, got[0].ordered)
+		require.Equal(t, SniperMode, got[0].mode)
+	})
+
+	t.Run("range placeholders are not directives", func(t *testing.T) {
+		got, err := compilePatterns([]string{"{{range:1-2}}-{{sub}}.{{suffix}}"})
+		require.NoError(t, err)
+		require.Empty(t, got[0].mode)
+	})
+
+	t.Run("invalid directives", func(t *testing.T) {
+		for _, v := range []string{"unknown:{{sub}}.{{suffix}}", "pitchfork,sniper:{{sub}}.{{suffix}}"} {
+			_, err := compilePatterns([]string{v})
+			require.Error(t, err, v)
+		}
+	})
+}
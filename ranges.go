@@ -0,0 +1,162 @@
+package alterx
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	// RangePrefix is prefix of inline range generator placeholders (ex: {{range:1-50:2}})
+	RangePrefix = "range:"
+	// rangeVarPrefix is prefix of variables generated for inline ranges
+	rangeVarPrefix = "_range"
+)
+
+// rangeRegex matches inline range generator placeholders
+var rangeRegex = regexp.MustCompile(`\{\{\s*` + RangePrefix + `([^{}|]*)((?:\|[^{}]*)?)\}\}`)
+
+// Range is a payload generator producing numbers from Start to End (inclusive)
+// numbers are generated lazily while iterating and are never stored in memory
+type Range struct {
+	// Start is first number of range
+	Start int `yaml:"start"`
+	// End is last number of range (inclusive)
+	End int `yaml:"end"`
+	// Step is increment between numbers (default: 1)
+	Step int `yaml:"step,omitempty"`
+	// Width zero pads numbers to given width (ex: width 2 => 01,02...)
+	Width int `yaml:"width,omitempty"`
+	// Base is numeric base of generated numbers (default: 10, ex: 16 for hex)
+	Base int `yaml:"base,omitempty"`
+}
+
+// Validate checks if range is valid
+func (r *Range) Validate() error {
+	if r.Start < 0 || r.End < r.Start {
+		return fmt.Errorf("invalid range %v-%v: start must be positive and less than or equal to end", r.Start, r.End)
+	}
+	if r.Step < 0 {
+		return fmt.Errorf("invalid range step %v: step must be positive", r.Step)
+	}
+	if r.Width < 0 {
+		return fmt.Errorf("invalid range width %v: width must be positive", r.Width)
+	}
+	if r.Base != 0 && (r.Base < 2 || r.Base > 36) {
+		return fmt.Errorf("invalid range base %v: base must be between 2 and 36", r.Base)
+	}
+	return nil
+}
+
+// Len returns number of values produced by range
+func (r *Range) Len() int {
+	if r.End < r.Start {
+		return 0
+	}
+	return (r.End-r.Start)/r.step() + 1
+}
+
+// At returns formatted number at given index
+func (r *Range) At(i int) string {
+	value := strconv.FormatInt(int64(r.Start+i*r.step()), r.base())
+	if len(value) < r.Width {
+		value = strings.Repeat("0", r.Width-len(value)) + value
+	}
+	return value
+}
+
+func (r *Range) step() int {
+	if r.Step <= 0 {
+		return 1
+	}
+	return r.Step
+}
+
+func (r *Range) base() int {
+	if r.Base == 0 {
+		return 10
+	}
+	return r.Base
+}
+
+// ParseRange parses inline range specification in `START-END[:STEP[:WIDTH[:BASE]]]` format
+// width is inferred from zero padded start (ex: 01-50) and base from 0x prefix (ex: 0x00-0xff)
+func ParseRange(spec string) (*Range, error) {
+	parts := strings.Split(strings.TrimSpace(spec), ":")
+	if len(parts) > 4 {
+		return nil, fmt.Errorf("invalid range `%v`: expected START-END[:STEP[:WIDTH[:BASE]]]", spec)
+	}
+	startStr, endStr, ok := strings.Cut(parts[0], "-")
+	if !ok {
+		return nil, fmt.Errorf("invalid range `%v`: expected START-END", spec)
+	}
+	r := &Range{}
+	if strings.HasPrefix(startStr, "0x") || strings.HasPrefix(endStr, "0x") {
+		r.Base = 16
+		startStr, endStr = strings.TrimPrefix(startStr, "0x"), strings.TrimPrefix(endStr, "0x")
+	}
+	if len(parts) > 3 {
+		base, err := strconv.Atoi(parts[3])
+		if err != nil {
+			return nil, fmt.Errorf("invalid range base `%v`", parts[3])
+		}
+		r.Base = base
+	}
+	if len(startStr) > 1 && strings.HasPrefix(startStr, "0") {
+		r.Width = len(startStr)
+	}
+	var err error
+	if r.Start, err = parseRangeInt(startStr, r.base()); err != nil {
+		return nil, err
+	}
+	if r.End, err = parseRangeInt(endStr, r.base()); err != nil {
+		return nil, err
+	}
+	if len(parts) > 1 && parts[1] != "" {
+		if r.Step, err = strconv.Atoi(parts[1]); err != nil || r.Step == 0 {
+			return nil, fmt.Errorf("invalid range step `%v`", parts[1])
+		}
+	}
+	if len(parts) > 2 && parts[2] != "" {
+		if r.Width, err = strconv.Atoi(parts[2]); err != nil {
+			return nil, fmt.Errorf("invalid range width `%v`", parts[2])
+		}
+	}
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// parseRangeInt parses number of range in given base
+func parseRangeInt(value string, base int) (int, error) {
+	if base < 2 || base > 36 {
+		return 0, fmt.Errorf("invalid range base %v: base must be between 2 and 36", base)
+	}
+	number, err := strconv.ParseInt(value, base, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid range number `%v`", value)
+	}
+	return int(number), nil
+}
+
+// extractRanges replaces all inline range placeholders in template with variables
+// and returns their generators. ex: `{{sub}}{{range:1-50:2|pad:3}}.{{suffix}}` => `{{sub}}{{_range1|pad:3}}.{{suffix}}`
+func extractRanges(template string) (string, map[string]Generator, error) {
+	matches := rangeRegex.FindAllStringSubmatch(template, -1)
+	if len(matches) == 0 {
+		return template, nil, nil
+	}
+	generators := map[string]Generator{}
+	for _, match := range matches {
+		r, err := ParseRange(match[1])
+		if err != nil {
+			return "", nil, err
+		}
+		name := rangeVarPrefix + strconv.Itoa(len(generators)+1)
+		generators[name] = r
+		template = strings.Replace(template, match[0], ParenthesisOpen+name+match[2]+ParenthesisClose, 1)
+	}
+	return template, generators, nil
+}
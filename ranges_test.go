@@ -0,0 +1,79 @@
+package alterx
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func getRangeValues(r *Range) []string {
+	var values []string
+	for i := 0; i < r.Len(); i++ {
+		values = append(values, r.At(i))
+	}
+	return values
+}
+
+func TestRange(t *testing.T) {
+	testcases := []struct {
+		name     string
+		r        *Range
+		expected []string
+	}{
+		{name: "default step", r: &Range{Start: 1, End: 5}, expected: []string{"1", "2", "3", "4", "5"}},
+		{name: "custom step", r: &Range{Start: 1, End: 10, Step: 3}, expected: []string{"1", "4", "7", "10"}},
+		{name: "zero padding", r: &Range{Start: 8, End: 11, Width: 2}, expected: []string{"08", "09", "10", "11"}},
+		{name: "hex", r: &Range{Start: 14, End: 17, Width: 2, Base: 16}, expected: []string{"0e", "0f", "10", "11"}},
+		{name: "single value", r: &Range{Start: 7, End: 7}, expected: []string{"7"}},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.NoError(t, tc.r.Validate())
+			require.Equal(t, len(tc.expected), tc.r.Len())
+			require.Equal(t, tc.expected, getRangeValues(tc.r))
+		})
+	}
+}
+
+func TestRangeValidate(t *testing.T) {
+	require.Error(t, (&Range{Start: 10, End: 1}).Validate())
+	require.Error(t, (&Range{Start: -1, End: 1}).Validate())
+	require.Error(t, (&Range{Start: 1, End: 10, Step: -1}).Validate())
+	require.Error(t, (&Range{Start: 1, End: 10, Base: 64}).Validate())
+}
+
+func TestParseRange(t *testing.T) {
+	testcases := []struct {
+		spec     string
+		expected *Range
+	}{
+		{spec: "1-50", expected: &Range{Start: 1, End: 50}},
+		{spec: "1-50:2", expected: &Range{Start: 1, End: 50, Step: 2}},
+		{spec: "01-50", expected: &Range{Start: 1, End: 50, Width: 2}},
+		{spec: "1-50:1:3", expected: &Range{Start: 1, End: 50, Step: 1, Width: 3}},
+		{spec: "0x00-0xff", expected: &Range{Start: 0, End: 255, Width: 2, Base: 16}},
+		{spec: "0-ff:16:2:16", expected: &Range{Start: 0, End: 255, Step: 16, Width: 2, Base: 16}},
+	}
+	for _, tc := range testcases {
+		got, err := ParseRange(tc.spec)
+		require.NoError(t, err, tc.spec)
+		require.Equal(t, tc.expected, got, tc.spec)
+	}
+
+	for _, v := range []string{"", "1", "a-b", "50-1", "1-5:x", "1-5:0", "1-5:1:2:99", "1-5:1:1:10:1"} {
+		_, err := ParseRange(v)
+		require.Error(t, err, v)
+	}
+}
+
+func TestExtractRanges(t *testing.T) {
+	template, generators, err := extractRanges("web{{range:01-03}}-{{range:1-9:4|pad:3}}.{{suffix}}")
+	require.NoError(t, err)
+	require.Equal(t, "web{{_range1}}-{{_range2|pad:3}}.{{suffix}}", template)
+	require.Len(t, generators, 2)
+	require.Equal(t, []string{"01", "02", "03"}, getRangeValues(generators["_range1"].(*Range)))
+	require.Equal(t, []string{"1", "5", "9"}, getRangeValues(generators["_range2"].(*Range)))
+
+	_, _, err = extractRanges("{{range:9-1}}.{{suffix}}")
+	require.Error(t, err)
+}
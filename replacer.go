@@ -2,8 +2,7 @@ package alterx
 
 import (
 	"fmt"
-
-	"github.com/projectdiscovery/fasttemplate"
+	"strings"
 )
 
 const (
@@ -16,12 +15,95 @@ const (
 )
 
 // Replace replaces placeholders in template with values on the fly.
+// Placeholders may contain filters (ex: {{sub|upper}}) which are applied
+// to value before replacement. Unknown placeholders are kept as is.
 func Replace(template string, values map[string]interface{}) string {
-	valuesMap := make(map[string]interface{}, len(values))
+	valuesMap := make(map[string]string, len(values))
 	for k, v := range values {
 		valuesMap[k] = fmt.Sprint(v)
 	}
-	replaced := fasttemplate.ExecuteStringStd(template, ParenthesisOpen, ParenthesisClose, valuesMap)
-	final := fasttemplate.ExecuteStringStd(replaced, General, General, valuesMap)
+	replaced := executeTemplate(template, ParenthesisOpen, ParenthesisClose, valuesMap)
+	final := executeTemplate(replaced, General, General, valuesMap)
 	return final
 }
+
+// executeTemplate replaces all placeholders between startTag and endTag
+// nested placeholders (ex: {{sub{{nested}}}}) are treated as single placeholder
+func executeTemplate(template, startTag, endTag string, values map[string]string) string {
+	if !strings.Contains(template, startTag) {
+		return template
+	}
+	var sb strings.Builder
+	sb.Grow(len(template))
+	for {
+		start := strings.Index(template, startTag)
+		if start < 0 {
+			break
+		}
+		sb.WriteString(template[:start])
+		template = template[start+len(startTag):]
+		end := findEndTag(template, startTag, endTag)
+		if end < 0 {
+			// cannot find end tag - just write it to the output
+			sb.WriteString(startTag)
+			break
+		}
+		tag := template[:end]
+		if value, ok := lookupTag(tag, values); ok {
+			sb.WriteString(value)
+		} else {
+			sb.WriteString(startTag + tag + endTag)
+		}
+		template = template[end+len(endTag):]
+	}
+	sb.WriteString(template)
+	return sb.String()
+}
+
+// findEndTag returns index of endTag that closes the placeholder
+// while skipping over nested placeholders
+func findEndTag(data, startTag, endTag string) int {
+	if startTag == endTag {
+		return strings.Index(data, endTag)
+	}
+	depth := 0
+	for i := 0; i < len(data); {
+		switch {
+		case strings.HasPrefix(data[i:], startTag):
+			depth++
+			i += len(startTag)
+		case strings.HasPrefix(data[i:], endTag):
+			if depth == 0 {
+				return i
+			}
+			depth--
+			i += len(endTag)
+		default:
+			i++
+		}
+	}
+	return -1
+}
+
+// lookupTag returns value of tag after applying filters if any
+func lookupTag(tag string, values map[string]string) (string, bool) {
+	if value, ok := values[tag]; ok {
+		return value, true
+	}
+	if !strings.Contains(tag, FilterSeparator) {
+		return "", false
+	}
+	name, chain, err := parsePlaceholder(tag)
+	if err != nil {
+		return "", false
+	}
+	value, ok := values[name]
+	if !ok {
+		return "", false
+	}
+	filtered, err := chain.apply(value)
+	if err != nil {
+		return "", false
+	}
+	return filtered, true
+}
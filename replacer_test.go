@@ -151,6 +151,28 @@ func TestReplace(t *testing.T) {
 		require.Equal(t, "value.example.com", result)
 	})
 
+	t.Run("filters", func(t *testing.T) {
+		template := "{{sub|upper}}-{{number|pad:3}}.{{root}}"
+		values := map[string]interface{}{
+			"sub":    "api",
+			"number": 7,
+			"root":   "example.com",
+		}
+
+		result := Replace(template, values)
+		require.Equal(t, "API-007.example.com", result)
+	})
+
+	t.Run("filters on missing variable leave placeholder", func(t *testing.T) {
+		template := "{{sub}}-{{word|upper}}.example.com"
+		values := map[string]interface{}{
+			"sub": "api",
+		}
+
+		result := Replace(template, values)
+		require.Equal(t, "api-{{word|upper}}.example.com", result)
+	})
+
 	t.Run("whitespace in placeholders", func(t *testing.T) {
 		template := "{{ word }}.example.com"
 		values := map[string]interface{}{
@@ -0,0 +1,116 @@
+package alterx
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ruleTokenSeparators are separators used to split candidates into tokens for MaxRepeat rule
+var ruleTokenSeparators = []string{".", "-"}
+
+// Rules contains exclusion rules evaluated on every generated candidate
+type Rules struct {
+	// Deny drops candidates matching any of these regexes
+	Deny []string `yaml:"deny"`
+	// Allow when not empty, drops candidates not matching at least one of these regexes
+	Allow []string `yaml:"allow"`
+	// Forbidden drops candidates containing any of these substrings
+	Forbidden []string `yaml:"forbidden"`
+	// DistinctFromSub drops candidates where a payload value is equal to {{sub}} of input (ex: api-api.example.com)
+	DistinctFromSub bool `yaml:"distinct-from-sub"`
+	// MaxRepeat drops candidates where same token occurs more than given times (0 = no limit)
+	// tokens are labels and their `-` separated parts excluding root
+	MaxRepeat int `yaml:"max-repeat"`
+
+	// internal or unexported variables
+	deny  []*regexp.Regexp
+	allow []*regexp.Regexp
+}
+
+// Compile validates and compiles regexes of rules
+func (r *Rules) Compile() error {
+	if r.MaxRepeat < 0 {
+		return fmt.Errorf("invalid max-repeat %v: must be positive", r.MaxRepeat)
+	}
+	var err error
+	if r.deny, err = compileRegexes(r.Deny); err != nil {
+		return fmt.Errorf("invalid deny rule: %w", err)
+	}
+	if r.allow, err = compileRegexes(r.Allow); err != nil {
+		return fmt.Errorf("invalid allow rule: %w", err)
+	}
+	return nil
+}
+
+// IsAllowed returns true if candidate with given root domain satisfies all rules
+// rules must be compiled using Compile before use
+func (r *Rules) IsAllowed(candidate string, root string) bool {
+	for _, v := range r.Forbidden {
+		if strings.Contains(candidate, v) {
+			return false
+		}
+	}
+	for _, v := range r.deny {
+		if v.MatchString(candidate) {
+			return false
+		}
+	}
+	if len(r.allow) > 0 {
+		matched := false
+		for _, v := range r.allow {
+			if v.MatchString(candidate) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	if r.MaxRepeat > 0 {
+		counts := map[string]int{}
+		for _, token := range tokenize(strings.TrimSuffix(candidate, "."+root), ruleTokenSeparators) {
+			counts[token]++
+			if counts[token] > r.MaxRepeat {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// hasSubValue returns true if any payload value of varMap is equal to sub
+func hasSubValue(varMap map[string]interface{}, vars []string, sub string) bool {
+	for _, v := range vars {
+		if value, ok := varMap[v].(string); ok && value == sub {
+			return true
+		}
+	}
+	return false
+}
+
+// compileRegexes compiles all given regexes
+func compileRegexes(values []string) ([]*regexp.Regexp, error) {
+	var compiled []*regexp.Regexp
+	for _, v := range values {
+		re, err := regexp.Compile(v)
+		if err != nil {
+			return nil, err
+		}
+		compiled = append(compiled, re)
+	}
+	return compiled, nil
+}
+
+// tokenize splits value into non-empty tokens using given separators
+func tokenize(value string, separators []string) []string {
+	return strings.FieldsFunc(value, func(r rune) bool {
+		for _, v := range separators {
+			if strings.ContainsRune(v, r) {
+				return true
+			}
+		}
+		return false
+	})
+}
@@ -0,0 +1,37 @@
+package alterx
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRules(t *testing.T) {
+	testcases := []struct {
+		name      string
+		rules     *Rules
+		candidate string
+		expected  bool
+	}{
+		{name: "no rules", rules: &Rules{}, candidate: "api-dev.example.com", expected: true},
+		{name: "deny", rules: &Rules{Deny: []string{`^test-`}}, candidate: "test-api.example.com", expected: false},
+		{name: "deny not matched", rules: &Rules{Deny: []string{`^test-`}}, candidate: "api-test.example.com", expected: true},
+		{name: "allow", rules: &Rules{Allow: []string{`^[a-z]+-(dev|prod)\.`}}, candidate: "api-dev.example.com", expected: true},
+		{name: "allow not matched", rules: &Rules{Allow: []string{`^[a-z]+-(dev|prod)\.`}}, candidate: "api-qa.example.com", expected: false},
+		{name: "forbidden", rules: &Rules{Forbidden: []string{"--"}}, candidate: "api--dev.example.com", expected: false},
+		{name: "max repeat", rules: &Rules{MaxRepeat: 1}, candidate: "api.dev-api.example.com", expected: false},
+		{name: "max repeat ignores root", rules: &Rules{MaxRepeat: 1}, candidate: "example.example.com", expected: true},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.NoError(t, tc.rules.Compile())
+			require.Equal(t, tc.expected, tc.rules.IsAllowed(tc.candidate, "example.com"))
+		})
+	}
+
+	t.Run("invalid rules", func(t *testing.T) {
+		require.Error(t, (&Rules{Deny: []string{"("}}).Compile())
+		require.Error(t, (&Rules{Allow: []string{"["}}).Compile())
+		require.Error(t, (&Rules{MaxRepeat: -1}).Compile())
+	})
+}
@@ -0,0 +1,101 @@
+package alterx
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	// DefaultSeriesWindow is window of numeric series extrapolation used by enrich
+	DefaultSeriesWindow = 5
+	// maxSeriesDigits is maximum length of numbers treated as part of a series
+	// longer numbers are usually ids or hashes (ex: build-20240101)
+	maxSeriesDigits = 6
+)
+
+// seriesNumber is a number found in subdomain labels of input
+type seriesNumber struct {
+	key   string // hostname with number replaced by placeholder (ex: web#.example.com)
+	value int
+	width int // width of zero padded numbers (0 if not padded)
+	start int
+	end   int
+}
+
+// numberSeries contains known numbers of inputs sharing same key
+type numberSeries struct {
+	numbers map[int]struct{}
+	width   int // largest width of zero padded numbers (ex: 2 for web09, web10)
+}
+
+// findSeriesNumbers returns numbers present in subdomain labels of hostname
+func findSeriesNumbers(hostname string, root string) []seriesNumber {
+	labels := strings.TrimSuffix(hostname, root)
+	var numbers []seriesNumber
+	for _, v := range extractNumbers.FindAllStringIndex(labels, -1) {
+		digits := labels[v[0]:v[1]]
+		if len(digits) > maxSeriesDigits {
+			continue
+		}
+		value, err := strconv.Atoi(digits)
+		if err != nil {
+			continue
+		}
+		number := seriesNumber{value: value, start: v[0], end: v[1]}
+		if len(digits) > 1 && digits[0] == '0' {
+			number.width = len(digits)
+		}
+		number.key = labels[:v[0]] + "#" + labels[v[1]:] + root
+		numbers = append(numbers, number)
+	}
+	return numbers
+}
+
+// addSeries registers numbers of input in their series
+func (m *Mutator) addSeries(input *Input) {
+	if m.Options.SeriesWindow <= 0 || input.Template != "" {
+		return
+	}
+	for _, v := range findSeriesNumbers(input.Hostname(), input.Root) {
+		series, ok := m.series[v.key]
+		if !ok {
+			series = &numberSeries{numbers: map[int]struct{}{}}
+			m.series[v.key] = series
+		}
+		series.numbers[v.value] = struct{}{}
+		series.width = max(series.width, v.width)
+	}
+}
+
+// extrapolateSeries generates hostnames by replacing every number of input with numbers
+// within window around it that are not present in its series (ex: web02 => web01, web03...web07)
+// zero padding of series and position of number are preserved. it stops early if callback returns false
+func (m *Mutator) extrapolateSeries(input *Input, callback func(string) bool) bool {
+	if m.Options.SeriesWindow <= 0 || input.Template != "" {
+		return true
+	}
+	hostname := input.Hostname()
+	for _, v := range findSeriesNumbers(hostname, input.Root) {
+		width := v.width
+		series := m.series[v.key]
+		if series != nil {
+			width = max(width, series.width)
+		}
+		// numbers below 1 are only generated if series already starts at 0
+		for n := max(v.value-m.Options.SeriesWindow, min(v.value, 1)); n <= v.value+m.Options.SeriesWindow; n++ {
+			if n == v.value {
+				continue
+			}
+			if series != nil {
+				if _, ok := series.numbers[n]; ok {
+					continue
+				}
+			}
+			if !callback(hostname[:v.start] + fmt.Sprintf("%0*d", width, n) + hostname[v.end:]) {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,74 @@
+package alterx
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindSeriesNumbers(t *testing.T) {
+	numbers := findSeriesNumbers("web01.dc2.example42.com", "example42.com")
+	require.Len(t, numbers, 2)
+	require.Equal(t, seriesNumber{key: "web#.dc2.example42.com", value: 1, width: 2, start: 3, end: 5}, numbers[0])
+	require.Equal(t, seriesNumber{key: "web01.dc#.example42.com", value: 2, start: 8, end: 9}, numbers[1])
+
+	require.Empty(t, findSeriesNumbers("build-20240101.example.com", "example.com"))
+}
+
+func TestExtrapolateSeries(t *testing.T) {
+	m := &Mutator{Options: &Options{SeriesWindow: 2}, series: map[string]*numberSeries{}}
+	var inputs []*Input
+	for _, v := range []string{"web01.example.com", "web02.example.com", "web05.example.com", "node-3.example.com", "v0.example.com"} {
+		input, err := NewInput(v)
+		require.NoError(t, err)
+		m.addSeries(input)
+		inputs = append(inputs, input)
+	}
+
+	testcases := []struct {
+		input    *Input
+		expected []string
+	}{
+		{input: inputs[0], expected: []string{"web03.example.com"}},
+		{input: inputs[1], expected: []string{"web03.example.com", "web04.example.com"}},
+		{input: inputs[2], expected: []string{"web03.example.com", "web04.example.com", "web06.example.com", "web07.example.com"}},
+		{input: inputs[3], expected: []string{"node-1.example.com", "node-2.example.com", "node-4.example.com", "node-5.example.com"}},
+		// numbers below 1 are generated only if series starts at 0
+		{input: inputs[4], expected: []string{"v1.example.com", "v2.example.com"}},
+	}
+	for _, tc := range testcases {
+		var got []string
+		m.extrapolateSeries(tc.input, func(value string) bool {
+			got = append(got, value)
+			return true
+		})
+		require.Equal(t, tc.expected, got, tc.input.Hostname())
+	}
+
+	t.Run("padding across digit boundary", func(t *testing.T) {
+		m := &Mutator{Options: &Options{SeriesWindow: 3}, series: map[string]*numberSeries{}}
+		var inputs []*Input
+		for _, v := range []string{"web08.example.com", "web09.example.com", "web10.example.com", "web11.example.com"} {
+			input, err := NewInput(v)
+			require.NoError(t, err)
+			m.addSeries(input)
+			inputs = append(inputs, input)
+		}
+		require.Len(t, m.series, 1)
+		var got []string
+		m.extrapolateSeries(inputs[2], func(value string) bool {
+			got = append(got, value)
+			return true
+		})
+		// web10 is not padded but series is
+		require.Equal(t, []string{"web07.example.com", "web12.example.com", "web13.example.com"}, got)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		m := &Mutator{Options: &Options{}, series: map[string]*numberSeries{}}
+		require.True(t, m.extrapolateSeries(inputs[0], func(value string) bool {
+			t.Fatalf("unexpected candidate %v", value)
+			return true
+		}))
+	})
+}
@@ -0,0 +1,140 @@
+package alterx
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+const (
+	// suffixCommentPrefix is prefix of comments in public suffix list files
+	suffixCommentPrefix = "//"
+	// suffixWildcardPrefix is prefix of wildcard rules (ex: *.compute.internal)
+	suffixWildcardPrefix = "*."
+	// suffixExceptionPrefix is prefix of exception rules (ex: !www.compute.internal)
+	suffixExceptionPrefix = "!"
+)
+
+// SuffixList contains additional public suffixes and root domains that are consulted
+// before embedded public suffix list (ex: corp.local, svc.cluster.local)
+type SuffixList struct {
+	suffixes   map[string]struct{}
+	wildcards  map[string]struct{}
+	exceptions map[string]struct{}
+	roots      map[string]struct{}
+}
+
+// NewSuffixList creates an empty suffix list
+func NewSuffixList() *SuffixList {
+	return &SuffixList{
+		suffixes:   map[string]struct{}{},
+		wildcards:  map[string]struct{}{},
+		exceptions: map[string]struct{}{},
+		roots:      map[string]struct{}{},
+	}
+}
+
+// AddSuffix adds a rule in public suffix list format (ex: local, *.compute.internal or !www.compute.internal)
+func (s *SuffixList) AddSuffix(rule string) error {
+	rule = strings.ToLower(strings.TrimSpace(rule))
+	target := s.suffixes
+	switch {
+	case strings.HasPrefix(rule, suffixExceptionPrefix):
+		rule, target = strings.TrimPrefix(rule, suffixExceptionPrefix), s.exceptions
+	case strings.HasPrefix(rule, suffixWildcardPrefix):
+		rule, target = strings.TrimPrefix(rule, suffixWildcardPrefix), s.wildcards
+	}
+	name, err := normalizeSuffix(rule)
+	if err != nil {
+		return err
+	}
+	target[name] = struct{}{}
+	return nil
+}
+
+// AddRoot adds a domain that is always treated as root domain (eTLD+1) of its subdomains
+// (ex: svc.cluster.local for api.svc.cluster.local)
+func (s *SuffixList) AddRoot(root string) error {
+	name, err := normalizeSuffix(strings.ToLower(strings.TrimSpace(root)))
+	if err != nil {
+		return err
+	}
+	if !strings.Contains(name, ".") {
+		return fmt.Errorf("invalid root `%v`: root must have at least two labels", root)
+	}
+	s.roots[name] = struct{}{}
+	return nil
+}
+
+// Load reads rules in public suffix list format from reader
+// empty lines and `//` comments are ignored
+func (s *SuffixList) Load(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, suffixCommentPrefix) {
+			continue
+		}
+		// rules end at first whitespace
+		if err := s.AddSuffix(strings.Fields(line)[0]); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// LoadFile reads rules in public suffix list format from file
+func (s *SuffixList) LoadFile(filePath string) error {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+	if err := s.Load(file); err != nil {
+		return fmt.Errorf("failed to read suffix list %v: %w", filePath, err)
+	}
+	return nil
+}
+
+// PublicSuffix returns public suffix of domain (in punycode form) if it is matched by list
+// roots take precedence over rules, and among rules exceptions take precedence over
+// longest matching rule like in public suffix list algorithm
+func (s *SuffixList) PublicSuffix(domain string) (string, bool) {
+	// rules are matched case insensitively while suffix is returned as is
+	labels := strings.Split(domain, ".")
+	lower := strings.Split(strings.ToLower(domain), ".")
+	for i := range lower {
+		if _, ok := s.roots[strings.Join(lower[i:], ".")]; ok {
+			return strings.Join(labels[i+1:], "."), true
+		}
+	}
+	for i := range lower {
+		if _, ok := s.exceptions[strings.Join(lower[i:], ".")]; ok {
+			return strings.Join(labels[i+1:], "."), true
+		}
+	}
+	for i := range lower {
+		if _, ok := s.suffixes[strings.Join(lower[i:], ".")]; ok {
+			return strings.Join(labels[i:], "."), true
+		}
+		if i+1 < len(lower) {
+			if _, ok := s.wildcards[strings.Join(lower[i+1:], ".")]; ok {
+				return strings.Join(labels[i:], "."), true
+			}
+		}
+	}
+	return "", false
+}
+
+// normalizeSuffix validates suffix and converts it to punycode form
+func normalizeSuffix(name string) (string, error) {
+	name = strings.Trim(name, ".")
+	if name == "" || strings.Contains(name, "..") || strings.Contains(name, "*") {
+		return "", fmt.Errorf("invalid suffix `%v`", name)
+	}
+	return toASCIIName(name)
+}
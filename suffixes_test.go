@@ -0,0 +1,59 @@
+package alterx
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSuffixList(t *testing.T) {
+	suffixes := NewSuffixList()
+	err := suffixes.Load(strings.NewReader(`// ===BEGIN PRIVATE DOMAINS===
+local
+*.compute.internal
+!www.compute.internal  extra text is ignored
+
+// roots can also be configured explicitly
+`))
+	require.NoError(t, err)
+	require.NoError(t, suffixes.AddRoot("svc.cluster.local"))
+
+	testcases := []struct {
+		domain   string
+		expected string
+		found    bool
+	}{
+		{domain: "api.corp.local", expected: "local", found: true},
+		{domain: "api.svc.cluster.local", expected: "cluster.local", found: true},
+		{domain: "svc.cluster.local", expected: "cluster.local", found: true},
+		{domain: "api.ec2.compute.internal", expected: "ec2.compute.internal", found: true},
+		{domain: "api.www.compute.internal", expected: "compute.internal", found: true},
+		{domain: "API.Corp.LOCAL", expected: "LOCAL", found: true},
+		{domain: "api.example.com", found: false},
+	}
+	for _, tc := range testcases {
+		got, found := suffixes.PublicSuffix(tc.domain)
+		require.Equal(t, tc.found, found, tc.domain)
+		require.Equal(t, tc.expected, got, tc.domain)
+	}
+
+	t.Run("invalid entries", func(t *testing.T) {
+		require.Error(t, suffixes.AddRoot("local"))
+		require.Error(t, suffixes.AddSuffix("a..b"))
+		require.Error(t, suffixes.AddSuffix("*.*.b"))
+	})
+
+	t.Run("load file", func(t *testing.T) {
+		filePath := filepath.Join(t.TempDir(), "suffixes.dat")
+		require.NoError(t, os.WriteFile(filePath, []byte("internal\n"), 0644))
+		suffixes := NewSuffixList()
+		require.NoError(t, suffixes.LoadFile(filePath))
+		got, found := suffixes.PublicSuffix("api.corp.internal")
+		require.True(t, found)
+		require.Equal(t, "internal", got)
+		require.Error(t, suffixes.LoadFile(filepath.Join(t.TempDir(), "missing.dat")))
+	})
+}
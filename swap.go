@@ -0,0 +1,105 @@
+package alterx
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// classMatch is an occurrence of a class member inside a label
+type classMatch struct {
+	start, end int
+	class      string
+}
+
+// validateClasses checks if all members of classes are valid label parts
+func validateClasses(classes map[string][]string) error {
+	for name, members := range classes {
+		if len(members) < 2 {
+			return fmt.Errorf("class '%s' must have at least two members", name)
+		}
+		for _, v := range members {
+			if v == "" || strings.Contains(v, ".") {
+				return fmt.Errorf("class '%s' has invalid member `%v`", name, v)
+			}
+		}
+	}
+	return nil
+}
+
+// swapTokens generates hostnames by replacing class members found in labels of input with
+// their siblings from same class (ex: api-dev.example.com => api-prod.example.com) and invokes
+// callback for each of them. only one token is swapped at a time and separators are preserved
+// it stops early if callback returns false
+func (m *Mutator) swapTokens(input *Input, callback func(string) bool) bool {
+	if !m.Options.TokenSwap || input.Sub == "" {
+		return true
+	}
+	labels := append([]string{input.Sub}, input.MultiLevel...)
+	for i, label := range labels {
+		for _, match := range m.findClassMembers(label, input.tokenizer) {
+			current := label[match.start:match.end]
+			for _, sibling := range m.Options.Classes[match.class] {
+				if strings.EqualFold(sibling, current) {
+					continue
+				}
+				swapped := append([]string{}, labels...)
+				swapped[i] = label[:match.start] + sibling + label[match.end:]
+				if !callback(strings.Join(append(swapped, input.Root), ".")) {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
+
+// findClassMembers returns occurrences of class members in label that start and end
+// at token boundaries (ex: dev in api-dev2 but not in devops)
+func (m *Mutator) findClassMembers(label string, tokenizer *Tokenizer) []classMatch {
+	if tokenizer == nil {
+		tokenizer = DefaultTokenizer
+	}
+	starts, ends := map[int]struct{}{}, map[int]struct{}{}
+	offset := 0
+	for _, v := range segmentLabel(label, tokenizer) {
+		if v.kind != 's' {
+			starts[offset] = struct{}{}
+			ends[offset+len(v.value)] = struct{}{}
+		}
+		offset += len(v.value)
+	}
+	lower := strings.ToLower(label)
+	var matches []classMatch
+	seen := map[classMatch]struct{}{}
+	for _, class := range m.classNames {
+		for _, member := range m.Options.Classes[class] {
+			member = strings.ToLower(member)
+			for from := 0; from < len(lower); {
+				index := strings.Index(lower[from:], member)
+				if index < 0 {
+					break
+				}
+				match := classMatch{start: from + index, end: from + index + len(member), class: class}
+				_, isStart := starts[match.start]
+				_, isEnd := ends[match.end]
+				if _, ok := seen[match]; isStart && isEnd && !ok {
+					seen[match] = struct{}{}
+					matches = append(matches, match)
+				}
+				from += index + 1
+			}
+		}
+	}
+	return matches
+}
+
+// sortedKeys returns sorted keys of classes
+func sortedKeys(classes map[string][]string) []string {
+	keys := make([]string, 0, len(classes))
+	for k := range classes {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
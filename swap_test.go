@@ -0,0 +1,56 @@
+package alterx
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSwapTokens(t *testing.T) {
+	classes := map[string][]string{
+		"env":     {"dev", "prod", "staging"},
+		"region":  {"us-east-1", "eu-west-1"},
+		"version": {"v1", "v2"},
+	}
+	m := &Mutator{Options: &Options{TokenSwap: true, Classes: classes}, classNames: sortedKeys(classes)}
+
+	testcases := []struct {
+		domain   string
+		expected []string
+	}{
+		{domain: "api-dev.example.com", expected: []string{"api-prod.example.com", "api-staging.example.com"}},
+		{domain: "api.v1.example.com", expected: []string{"api.v2.example.com"}},
+		{domain: "dev2.us-east-1.example.com", expected: []string{"prod2.us-east-1.example.com", "staging2.us-east-1.example.com", "dev2.eu-west-1.example.com"}},
+		{domain: "API_Dev.example.com", expected: []string{"API_prod.example.com", "API_staging.example.com"}},
+		// members must be whole tokens
+		{domain: "devops.v10.example.com"},
+		{domain: "example.com"},
+	}
+	for _, tc := range testcases {
+		input, err := NewInput(tc.domain)
+		require.NoError(t, err)
+		var got []string
+		m.swapTokens(input, func(value string) bool {
+			got = append(got, value)
+			return true
+		})
+		require.ElementsMatch(t, tc.expected, got, tc.domain)
+	}
+
+	t.Run("stops early", func(t *testing.T) {
+		input, err := NewInput("api-dev.example.com")
+		require.NoError(t, err)
+		count := 0
+		require.False(t, m.swapTokens(input, func(value string) bool {
+			count++
+			return false
+		}))
+		require.Equal(t, 1, count)
+	})
+}
+
+func TestValidateClasses(t *testing.T) {
+	require.NoError(t, validateClasses(map[string][]string{"env": {"dev", "prod"}}))
+	require.Error(t, validateClasses(map[string][]string{"env": {"dev"}}))
+	require.Error(t, validateClasses(map[string][]string{"env": {"dev", "prod.internal"}}))
+}
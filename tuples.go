@@ -0,0 +1,77 @@
+package alterx
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// TupleFieldSeparator separates name of tuple payload and its field (ex: {{env.abbr}})
+const TupleFieldSeparator = "."
+
+// tupleFieldRegex matches valid field names of tuple payloads
+var tupleFieldRegex = regexp.MustCompile(`^[a-zA-Z0-9_]+$`)
+
+// Tuple is a payload entry consisting of correlated fields (ex: env=production, abbr=prod)
+type Tuple map[string]string
+
+// Tuples is a Generator of tuple payload entries. values produced by it are positions
+// of entries which are expanded to variables of their fields using Expand
+type Tuples []Tuple
+
+// Len returns number of entries
+func (t Tuples) Len() int {
+	return len(t)
+}
+
+// At returns position of entry at given index
+func (t Tuples) At(i int) string {
+	return strconv.Itoa(i)
+}
+
+// Expand adds all fields of entry at given position to varMap as `name.field` variables
+func (t Tuples) Expand(name string, position string, varMap map[string]interface{}) {
+	i, err := strconv.Atoi(position)
+	if err != nil || i < 0 || i >= len(t) {
+		return
+	}
+	for field, value := range t[i] {
+		varMap[name+TupleFieldSeparator+field] = value
+	}
+}
+
+// Fields returns sorted field names of entries
+func (t Tuples) Fields() []string {
+	if len(t) == 0 {
+		return nil
+	}
+	fields := make([]string, 0, len(t[0]))
+	for k := range t[0] {
+		fields = append(fields, k)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// Validate checks if all entries have same valid fields
+func (t Tuples) Validate() error {
+	fields := t.Fields()
+	for _, v := range fields {
+		if !tupleFieldRegex.MatchString(v) {
+			return fmt.Errorf("invalid tuple field `%v`: field names can only contain letters, digits and underscore", v)
+		}
+	}
+	for i, entry := range t {
+		if len(entry) != len(fields) {
+			return fmt.Errorf("tuple entry %v must have fields %v", i+1, strings.Join(fields, ","))
+		}
+		for _, v := range fields {
+			if _, ok := entry[v]; !ok {
+				return fmt.Errorf("tuple entry %v is missing field `%v`", i+1, v)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package alterx
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTuples(t *testing.T) {
+	tuples := Tuples{
+		{"name": "production", "abbr": "prod"},
+		{"name": "staging", "abbr": "stg"},
+	}
+
+	t.Run("generator", func(t *testing.T) {
+		require.Equal(t, 2, tuples.Len())
+		require.Equal(t, "1", tuples.At(1))
+		require.Equal(t, []string{"abbr", "name"}, tuples.Fields())
+	})
+
+	t.Run("expand", func(t *testing.T) {
+		varMap := map[string]interface{}{"env": "1"}
+		tuples.Expand("env", "1", varMap)
+		require.Equal(t, "staging", varMap["env.name"])
+		require.Equal(t, "stg", varMap["env.abbr"])
+		require.Equal(t, "staging-stg", Replace("{{env.name}}-{{env.abbr}}", varMap))
+	})
+
+	t.Run("validate", func(t *testing.T) {
+		require.NoError(t, tuples.Validate())
+		require.Error(t, Tuples{{"name": "production"}, {"abbr": "stg"}}.Validate())
+		require.Error(t, Tuples{{"name": "production"}, {"name": "staging", "abbr": "stg"}}.Validate())
+		require.Error(t, Tuples{{"short-name": "prod"}}.Validate())
+	})
+}
@@ -3,11 +3,17 @@ package alterx
 import (
 	"fmt"
 	"regexp"
+	"slices"
 	"strings"
 	"unsafe"
 )
 
-var varRegex = regexp.MustCompile(`\{\{([a-zA-Z0-9]+)\}\}`)
+var (
+	// varRegex matches variables with optional filters (ex: {{sub}}, {{sub|upper}}, {{env.abbr}} or {{word#1}})
+	varRegex = regexp.MustCompile(`\{\{([a-zA-Z0-9_]+(?:\.[a-zA-Z0-9_]+)?(?:#[0-9]+)?)(?:\|[^{}]+)?\}\}`)
+	// placeholderRegex matches content of any placeholder
+	placeholderRegex = regexp.MustCompile(`\{\{([^{}]+)\}\}`)
+)
 
 // returns no of variables present in statement
 func getVarCount(data string) int {
@@ -39,6 +45,15 @@ func getSampleMap(inputVars map[string]interface{}, payloadVars map[string][]str
 	return sMap
 }
 
+// copyPayloads returns copy of payloads and their values
+func copyPayloads(payloads map[string][]string) map[string][]string {
+	copied := make(map[string][]string, len(payloads))
+	for k, v := range payloads {
+		copied[k] = slices.Clone(v)
+	}
+	return copied
+}
+
 // checkMissing checks if all variables/placeholders are successfully replaced
 // if not error is thrown with description
 func checkMissing(template string, data map[string]interface{}) error {
@@ -49,6 +64,15 @@ func checkMissing(template string, data map[string]interface{}) error {
 	return nil
 }
 
+// getMissingVars returns names of variables of template that are not present in data
+func getMissingVars(template string, data map[string]interface{}) []string {
+	var missing []string
+	for _, v := range varRegex.FindAllStringSubmatch(Replace(template, data), -1) {
+		missing = append(missing, v[1])
+	}
+	return missing
+}
+
 // TODO: add this to utils
 // unsafeToBytes converts a string to byte slice and does it with
 // zero allocations.
@@ -21,6 +21,7 @@ func TestGetVarCount(t *testing.T) {
 		{"empty string", "", 0},
 		{"malformed brackets", "{{word}.example.com", 0},
 		{"single brackets", "{word}.example.com", 0},
+		{"variable with filters", "{{sub|upper}}-{{number|pad:3}}.com", 2},
 	}
 
 	for _, tt := range tests {
@@ -72,6 +73,16 @@ func TestGetAllVars(t *testing.T) {
 			input:    "{{word.example.com",
 			expected: nil,
 		},
+		{
+			name:     "variables with filters",
+			input:    "{{sub|replace:-:}}.{{word|upper|truncate:3}}.{{root}}",
+			expected: []string{"sub", "word", "root"},
+		},
+		{
+			name:     "tuple fields and instances",
+			input:    "{{word#1}}.{{word#2|upper}}-{{env.abbr}}.{{root}}",
+			expected: []string{"word#1", "word#2", "env.abbr", "root"},
+		},
 		{
 			name:     "mixed valid and invalid",
 			input:    "{{valid}}.{invalid}.{{another}}",
@@ -229,6 +240,12 @@ func TestCheckMissing(t *testing.T) {
 	})
 }
 
+func TestGetMissingVars(t *testing.T) {
+	data := map[string]interface{}{"word": "api", "root": "example.com"}
+	require.Empty(t, getMissingVars("{{word}}.{{root}}", data))
+	require.Equal(t, []string{"env", "sub1"}, getMissingVars("{{word}}-{{env|upper}}.{{sub1}}.{{root}}", data))
+}
+
 func TestUnsafeToBytes(t *testing.T) {
 	t.Run("basic string conversion", func(t *testing.T) {
 		str := "hello world"
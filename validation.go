@@ -0,0 +1,110 @@
+package alterx
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Validation modes of generated candidates
+const (
+	// ValidationStrict drops candidates that are not valid hostnames
+	ValidationStrict = "strict"
+	// ValidationLenient repairs empty labels and hyphens at edges of labels, allows
+	// underscores (ex: _dmarc) and drops other invalid candidates
+	ValidationLenient = "lenient"
+	// ValidationOff disables validation of candidates
+	ValidationOff = "off"
+)
+
+// ValidationModes contains all available validation modes
+var ValidationModes = []string{ValidationStrict, ValidationLenient, ValidationOff}
+
+// Rejection reasons of invalid candidates
+const (
+	RejectEmptyLabel   = "empty-label"
+	RejectInvalidChar  = "invalid-char"
+	RejectHyphen       = "hyphen"
+	RejectLabelTooLong = "label-too-long"
+	RejectNameTooLong  = "name-too-long"
+	RejectInvalidIDN   = "invalid-idn"
+)
+
+const (
+	// maxLabelLength is maximum length of a dns label in bytes
+	maxLabelLength = 63
+	// maxNameLength is maximum length of a dns name in bytes
+	maxNameLength = 253
+)
+
+// validateValidationMode checks if given validation mode is supported
+func validateValidationMode(mode string) error {
+	for _, v := range ValidationModes {
+		if v == mode {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown validation mode '%s': supported values are %s", mode, strings.Join(ValidationModes, ","))
+}
+
+// validateCandidate validates candidate hostname using given validation mode
+// it returns candidate (repaired in lenient mode) and reason if candidate is rejected
+func validateCandidate(candidate string, mode string) (string, string) {
+	if mode == ValidationOff {
+		return candidate, ""
+	}
+	lenient := mode == ValidationLenient
+	labels := strings.Split(candidate, ".")
+	if lenient {
+		repaired := labels[:0]
+		for _, label := range labels {
+			if label = strings.Trim(label, "-"); label != "" {
+				repaired = append(repaired, label)
+			}
+		}
+		labels = repaired
+		candidate = strings.Join(labels, ".")
+		if len(labels) == 0 {
+			return candidate, RejectEmptyLabel
+		}
+	}
+	for _, label := range labels {
+		if label == "" {
+			return candidate, RejectEmptyLabel
+		}
+		for i := 0; i < len(label); i++ {
+			if !isHostnameChar(label[i]) && !(lenient && label[i] == '_') {
+				return candidate, RejectInvalidChar
+			}
+		}
+		if label[0] == '-' || label[len(label)-1] == '-' {
+			return candidate, RejectHyphen
+		}
+		if len(label) > maxLabelLength {
+			return candidate, RejectLabelTooLong
+		}
+	}
+	if len(candidate) > maxNameLength {
+		return candidate, RejectNameTooLong
+	}
+	return candidate, ""
+}
+
+// isHostnameChar returns true if character is allowed in hostname labels
+func isHostnameChar(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') || c == '-'
+}
+
+// formatRejections returns rejection counts sorted by reason (ex: hyphen: 2, label-too-long: 1)
+func formatRejections(rejections map[string]int) string {
+	reasons := make([]string, 0, len(rejections))
+	for k := range rejections {
+		reasons = append(reasons, k)
+	}
+	sort.Strings(reasons)
+	parts := make([]string, 0, len(reasons))
+	for _, v := range reasons {
+		parts = append(parts, fmt.Sprintf("%s: %d", v, rejections[v]))
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,41 @@
+package alterx
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateCandidate(t *testing.T) {
+	testcases := []struct {
+		candidate string
+		mode      string
+		expected  string
+		reason    string
+	}{
+		{candidate: "api-dev.example.com", mode: ValidationStrict, expected: "api-dev.example.com"},
+		{candidate: "api..example.com", mode: ValidationStrict, expected: "api..example.com", reason: RejectEmptyLabel},
+		{candidate: "api-.example.com", mode: ValidationStrict, expected: "api-.example.com", reason: RejectHyphen},
+		{candidate: "_dmarc.example.com", mode: ValidationStrict, expected: "_dmarc.example.com", reason: RejectInvalidChar},
+		{candidate: "api dev.example.com", mode: ValidationStrict, expected: "api dev.example.com", reason: RejectInvalidChar},
+		{candidate: strings.Repeat("a", 64) + ".example.com", mode: ValidationStrict, expected: strings.Repeat("a", 64) + ".example.com", reason: RejectLabelTooLong},
+		{candidate: strings.Repeat(strings.Repeat("a", 60)+".", 5) + "example.com", mode: ValidationStrict, expected: strings.Repeat(strings.Repeat("a", 60)+".", 5) + "example.com", reason: RejectNameTooLong},
+		// lenient mode repairs empty labels and hyphens
+		{candidate: ".api..-dev-.example.com", mode: ValidationLenient, expected: "api.dev.example.com"},
+		{candidate: "_dmarc.example.com", mode: ValidationLenient, expected: "_dmarc.example.com"},
+		{candidate: "api$.example.com", mode: ValidationLenient, expected: "api$.example.com", reason: RejectInvalidChar},
+		{candidate: "-.-", mode: ValidationLenient, expected: "", reason: RejectEmptyLabel},
+		// validation is disabled
+		{candidate: "api..-.example.com", mode: ValidationOff, expected: "api..-.example.com"},
+	}
+	for _, tc := range testcases {
+		got, reason := validateCandidate(tc.candidate, tc.mode)
+		require.Equal(t, tc.expected, got, tc.candidate)
+		require.Equal(t, tc.reason, reason, tc.candidate)
+	}
+}
+
+func TestFormatRejections(t *testing.T) {
+	require.Equal(t, "hyphen: 2, label-too-long: 1", formatRejections(map[string]int{RejectLabelTooLong: 1, RejectHyphen: 2}))
+}